@@ -0,0 +1,18 @@
+(function () {
+  var input = document.getElementById('column-search');
+  if (!input) {
+    return;
+  }
+
+  var rows = document.querySelectorAll('#column-index tbody tr');
+  input.addEventListener('input', function () {
+    var keywords = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    for (var i = 0; i < rows.length; i++) {
+      var text = rows[i].getAttribute('data-search') || '';
+      var matched = keywords.every(function (k) {
+        return text.indexOf(k) >= 0;
+      });
+      rows[i].style.display = matched ? '' : 'none';
+    }
+  });
+})();
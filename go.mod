@@ -3,14 +3,20 @@ module github.com/yinheli/database-struct
 go 1.14
 
 require (
+	cloud.google.com/go/spanner v1.11.0
+	github.com/aws/aws-sdk-go v1.35.37
 	github.com/dave/jennifer v1.4.1
+	github.com/denisenkom/go-mssqldb v0.0.0-20191124224453-732737034ffd
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/gobuffalo/here v0.6.2 // indirect
 	github.com/jinzhu/gorm v1.9.16
+	github.com/lib/pq v1.1.1
 	github.com/magefile/mage v1.10.0
 	github.com/markbates/pkger v0.17.1
-	github.com/mattn/go-sqlite3 v2.0.3+incompatible // indirect
+	github.com/mattn/go-sqlite3 v2.0.3+incompatible
 	github.com/spf13/cobra v1.1.1
 	github.com/stretchr/testify v1.6.1
+	google.golang.org/api v0.33.0
 	gopkg.in/flosch/pongo2.v3 v3.0.0-20141028000813-5e81b817a0c4
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,36 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goAccessors `Get<Field>` and `Set<Field>` methods guarded by mutex field `mu` of model
+func goAccessors(options *Options, name string, table *Table) jen.Code {
+	base := baseField(options, table)
+	recv := receiverName(options, name, "m")
+	c := jen.Null()
+	n := 0
+	for _, f := range table.Fields {
+		if f == base || f.embed != nil {
+			continue
+		}
+		if n > 0 {
+			c.Line().Line()
+		}
+		n++
+
+		field := goFieldName(f)
+		mu := jen.Id(recv).Dot("mu")
+		c.Commentf("Get%s returns %s with read lock", field, field).Line().
+			Func().Params(jen.Id(recv).Op("*").Id(name)).Id("Get"+field).Params().Add(goFieldType(options, f, jen.Null())).Block(
+			mu.Clone().Dot("RLock").Call(),
+			jen.Defer().Add(mu.Clone()).Dot("RUnlock").Call(),
+			jen.Return(jen.Id(recv).Dot(field)),
+		).Line().Line().
+			Commentf("Set%s sets %s with write lock", field, field).Line().
+			Func().Params(jen.Id(recv).Op("*").Id(name)).Id("Set"+field).Params(goFieldType(options, f, jen.Id("v"))).Block(
+			mu.Clone().Dot("Lock").Call(),
+			jen.Defer().Add(mu.Clone()).Dot("Unlock").Call(),
+			jen.Id(recv).Dot(field).Op("=").Id("v"),
+		)
+	}
+	return c
+}
@@ -0,0 +1,39 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// baseField returns primary key field of table folded into options.BaseStruct, nil if table not match
+func baseField(options *Options, table *Table) *Field {
+	if options.BaseStruct == "" {
+		return nil
+	}
+
+	column, goType := options.BaseColumn, options.BaseGoType
+	if column == "" {
+		column = "id"
+	}
+	if goType == "" {
+		goType = "int64"
+	}
+
+	pks := primaryKeys(table.Fields)
+	if len(pks) != 1 || pks[0].Field != column || pks[0].GoType != goType || pks[0].Nullable {
+		return nil
+	}
+	if _, ok := typeOverride(options, pks[0]); ok {
+		return nil
+	}
+	return pks[0]
+}
+
+// goBaseStruct shared base struct embedded by tables with matching primary key,
+// the field is generated from the first matching table
+func goBaseStruct(options *Options, tables []*Table) jen.Code {
+	for _, table := range tables {
+		if f := baseField(options, table); f != nil {
+			return jen.Commentf("%s common primary key embedded by models", options.BaseStruct).Line().
+				Type().Id(options.BaseStruct).Struct(goField(options, table, f))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import "strings"
+
+// bool strategies of tinyint columns, see Options.BoolStrategy
+const (
+	BoolTinyint1   = "tinyint1"
+	BoolAllTinyint = "all-tinyint"
+	BoolNone       = "none"
+	BoolByName     = "by-name"
+)
+
+// defaultBoolNamePatterns column name patterns of BoolByName if Options.BoolNamePatterns is not set
+var defaultBoolNamePatterns = []string{"is_*", "has_*", "can_*"}
+
+// tinyintColumn returns true if column is tinyint, oracle NUMBER(1) is tinyint(1)
+func tinyintColumn(field *Field) bool {
+	return strings.HasPrefix(field.Type, "tinyint") || field.Type == "NUMBER(1)"
+}
+
+// boolColumn returns true if tinyint column is bool by options.BoolStrategy
+func boolColumn(options *Options, table *Table, field *Field) bool {
+	if !tinyintColumn(field) {
+		return false
+	}
+
+	switch options.BoolStrategy {
+	case BoolTinyint1:
+		return strings.HasPrefix(field.Type, "tinyint(1)") || field.Type == "NUMBER(1)"
+	case BoolAllTinyint:
+		return true
+	case BoolByName:
+		patterns := options.BoolNamePatterns
+		if len(patterns) == 0 {
+			patterns = defaultBoolNamePatterns
+		}
+		return columnMatch(patterns, table, field)
+	}
+	return false
+}
+
+// resolveBools map go type of tinyint columns to bool by options.BoolStrategy, or back to int8 and uint8
+func resolveBools(options *Options, tables []*Table) {
+	if options.BoolStrategy == "" {
+		return
+	}
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			switch {
+			case boolColumn(options, table, f):
+				f.GoType = "bool"
+			case f.GoType == "bool" && tinyintColumn(f):
+				f.GoType = "int8"
+				if strings.Contains(f.Type, "unsigned") {
+					f.GoType = "uint8"
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mysqlCheckRegexp check constraint line of `show create table`,
+// e.g. CONSTRAINT `user_chk_1` CHECK ((`age` >= 0)) /*!80016 NOT ENFORCED */
+var mysqlCheckRegexp = regexp.MustCompile("(?i)^\\s*CONSTRAINT\\s+`([^`]+)`\\s+CHECK\\s+\\((.*)\\)(\\s*/\\*.*\\*/)?\\s*,?$")
+
+var mysqlIdentRegexp = regexp.MustCompile("`([^`]+)`")
+
+// parseMysqlChecks assign check constraints of ddl to fields, constraint refers more than one column is skipped
+func parseMysqlChecks(ddl string, fields []*Field) {
+	for _, line := range strings.Split(ddl, "\n") {
+		m := mysqlCheckRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		columns := make(map[string]bool)
+		for _, ident := range mysqlIdentRegexp.FindAllStringSubmatch(m[2], -1) {
+			columns[ident[1]] = true
+		}
+		if len(columns) != 1 {
+			continue
+		}
+
+		for _, f := range fields {
+			if columns[f.Field] {
+				f.CheckName = m[1]
+				f.Check = strings.ReplaceAll(m[2], "`", "")
+			}
+		}
+	}
+}
+
+// postgresqlCheckExpr expression of pg_get_constraintdef, e.g. CHECK ((age >= 0)) NOT VALID
+func postgresqlCheckExpr(def string) string {
+	def = strings.TrimSuffix(strings.TrimSpace(def), " NOT VALID")
+	def = strings.TrimPrefix(def, "CHECK ")
+	if strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") {
+		def = def[1 : len(def)-1]
+	}
+	return def
+}
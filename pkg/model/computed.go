@@ -0,0 +1,33 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mysqlGeneratedRegexp generated column line of `show create table`,
+// e.g. `full_name` varchar(128) GENERATED ALWAYS AS (concat(`first`,' ',`last`)) VIRTUAL
+var mysqlGeneratedRegexp = regexp.MustCompile("(?i)^\\s*`([^`]+)`\\s.*?\\s(?:GENERATED ALWAYS\\s+)?AS\\s+\\((.*)\\)\\s+(VIRTUAL|STORED)")
+
+// parseMysqlGenerated set generation expression of generated columns from ddl, extra of them is VIRTUAL/STORED GENERATED
+func parseMysqlGenerated(ddl string, fields []*Field) {
+	generated := make(map[string]*Field)
+	for _, f := range fields {
+		if strings.Contains(strings.ToUpper(f.Extra), "GENERATED") {
+			generated[f.Field] = f
+		}
+	}
+	if len(generated) == 0 {
+		return
+	}
+
+	for _, line := range strings.Split(ddl, "\n") {
+		m := mysqlGeneratedRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if f, ok := generated[m[1]]; ok {
+			f.Generated = m[2]
+		}
+	}
+}
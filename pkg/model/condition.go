@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// goConstants constants of columns of options.GenConditions and options.GenTypeConstants
+func goConstants(options *Options, name string, table *Table) []jen.Code {
+	if len(table.Fields) == 0 {
+		return nil
+	}
+	cs := make([]jen.Code, 0, 2)
+	if options.GenConditions {
+		cs = append(cs, goConditions(name, table))
+	}
+	if options.GenTypeConstants {
+		cs = append(cs, goTypeConstants(name, table))
+	}
+	return cs
+}
+
+// goConditions where condition constants of columns, e.g. UserWhereId = "id = ?",
+// nullable columns also have UserWhereDeletedAtIsNull
+func goConditions(name string, table *Table) jen.Code {
+	defs := make([]jen.Code, 0, len(table.Fields))
+	for _, f := range table.Fields {
+		field := goFieldName(f)
+		defs = append(defs, jen.Id(name+"Where"+field).Op("=").Lit(f.Field+" = ?"))
+		if f.Nullable {
+			defs = append(defs, jen.Id(name+"Where"+field+"IsNull").Op("=").Lit(f.Field+" IS NULL"))
+		}
+	}
+	return jen.Commentf("where conditions of %s columns", table.Name).Line().
+		Const().Defs(defs...)
+}
+
+// goTypeConstants db type constants of columns for raw sql casts, e.g. UserIdType = "bigint"
+func goTypeConstants(name string, table *Table) jen.Code {
+	defs := make([]jen.Code, 0, len(table.Fields))
+	for _, f := range table.Fields {
+		defs = append(defs, jen.Id(name+goFieldName(f)+"Type").Op("=").Lit(f.Type))
+	}
+	return jen.Commentf("db types of %s columns, e.g. CAST(? AS %s)", table.Name, table.Fields[0].Type).Line().
+		Const().Defs(defs...)
+}
+
+// goSequenceConstants name and nextval query constants of options.Sequences, e.g. InvoiceNoSequence = "invoice_no",
+// InvoiceNoSequenceNextval = `SELECT nextval('"invoice_no"')`, nil without sequences
+func goSequenceConstants(options *Options) jen.Code {
+	if !options.GenSequenceConstants || len(options.Sequences) == 0 {
+		return nil
+	}
+	defs := make([]jen.Code, 0, len(options.Sequences)*2)
+	for _, name := range options.Sequences {
+		id := TitleCase(name) + "Sequence"
+		defs = append(defs,
+			jen.Id(id).Op("=").Lit(name),
+			jen.Id(id+"Nextval").Op("=").Lit(fmt.Sprintf("SELECT nextval('%s')", strings.ReplaceAll(sqlIdent(DbTypePostgreSQL, name), "'", "''"))),
+		)
+	}
+	return jen.Comment("sequences not owned by columns").Line().
+		Const().Defs(defs...)
+}
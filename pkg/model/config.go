@@ -0,0 +1,183 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrConfigFormat = errors.New("config format not supported, use yaml or json")
+
+// TableConfig settings of a table in config file, see Options.Tables
+type TableConfig struct {
+	// StructName go struct name of the model
+	StructName string
+	// ExcludeColumns columns not generated
+	ExcludeColumns []string
+	// Columns settings of columns keyed by column name
+	Columns map[string]*ColumnConfig
+	// Embed go structs embedded in the model, qualified by import path, e.g. gorm.io/gorm.Model,
+	// columns of gorm.Model are excluded
+	Embed []string
+}
+
+// ColumnConfig settings of a column in config file, see TableConfig.Columns
+type ColumnConfig struct {
+	// Name go field name
+	Name string
+	// Type go type, as TypeOverrides of `table.column`
+	Type string
+	// Tags extra struct tags, keys generated by the generator are kept
+	Tags map[string]string
+}
+
+// gormModelColumns columns of embedded gorm.Model
+var gormModelColumns = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+// LoadConfig load yaml (or json) config file into options, keys are Options field names case insensitive,
+// e.g. dbType, genGormTag, tables, fields not in the file are kept
+func LoadConfig(file string, options *Options) error {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("%w: %s", ErrConfigFormat, file)
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(node.Content) == 0 {
+		return nil
+	}
+	foldConfigKeys(node.Content[0], reflect.TypeOf(options))
+	if err = node.Content[0].Decode(options); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// foldConfigKeys lower case keys of mappings decoded to structs, yaml matches lower case field names,
+// keys of maps are kept
+func foldConfigKeys(node *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				fields[strings.ToLower(f.Name)] = f.Type
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := strings.ToLower(node.Content[i].Value)
+			if ft, ok := fields[key]; ok {
+				node.Content[i].Value = key
+				foldConfigKeys(node.Content[i+1], ft)
+			}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			foldConfigKeys(node.Content[i+1], t.Elem())
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, it := range node.Content {
+			foldConfigKeys(it, t.Elem())
+		}
+	}
+}
+
+// resolveTableConfigs apply options.Tables to tables: struct names, go names, types and tags of columns,
+// excluded columns and columns of embedded gorm.Model are removed
+func resolveTableConfigs(options *Options, tables []*Table) {
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			f.configTags = nil
+		}
+
+		config := options.Tables[table.Name]
+		if config == nil {
+			continue
+		}
+		if config.StructName != "" {
+			table.goName = config.StructName
+		}
+
+		exclude := make(map[string]bool, len(config.ExcludeColumns))
+		for _, it := range config.ExcludeColumns {
+			exclude[it] = true
+		}
+		for _, it := range config.Embed {
+			if path, name := embedStruct(it); name == "Model" && (path == "gorm.io/gorm" || path == "github.com/jinzhu/gorm") {
+				for _, column := range gormModelColumns {
+					exclude[column] = true
+				}
+			}
+		}
+		fields := make([]*Field, 0, len(table.Fields))
+		for _, f := range table.Fields {
+			if !exclude[f.Field] {
+				fields = append(fields, f)
+			}
+		}
+		table.Fields = fields
+
+		for _, f := range table.Fields {
+			column := config.Columns[f.Field]
+			if column == nil {
+				continue
+			}
+			if column.Name != "" {
+				f.goName = column.Name
+			}
+			if column.Type != "" {
+				f.override = column.Type
+			}
+			f.configTags = column.Tags
+		}
+	}
+}
+
+// embedStruct import path and name of qualified struct, e.g. gorm.io/gorm.Model
+func embedStruct(s string) (string, string) {
+	i := strings.LastIndex(s, ".")
+	if i < 0 || i < strings.LastIndex(s, "/") {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
+
+// goConfigEmbeds embedded struct fields of TableConfig.Embed of table
+func goConfigEmbeds(options *Options, table *Table) []jen.Code {
+	config := options.Tables[table.Name]
+	if config == nil {
+		return nil
+	}
+	cs := make([]jen.Code, 0, len(config.Embed))
+	for _, it := range config.Embed {
+		path, name := embedStruct(it)
+		cs = append(cs, jen.Qual(path, name))
+	}
+	return cs
+}
@@ -0,0 +1,1162 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// ddlFile tables of CREATE TABLE statements of a sql dump, see Options.SqlFile, column types are of
+// options.DbType, mysql or postgresql
+type ddlFile struct{}
+
+func (t *ddlFile) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
+	p := &ddlParser{byName: make(map[string]*Table), enums: make(map[string][]string), refs: make(map[*ForeignKey][]string)}
+	switch options.DbType {
+	case DbTypeMySQL:
+		p.mysql = true
+		p.searchPath = []string{""}
+	case DbTypePostgreSQL, DbTypeRedshift:
+		p.searchPath = []string{"public"}
+	default:
+		return nil, fmt.Errorf("%w: sql file of %s", ErrTypeNotSupported, options.DbType)
+	}
+
+	b, err := ioutil.ReadFile(options.SqlFile)
+	if err != nil {
+		return
+	}
+
+	if options.Verbose {
+		l.Println("parse sql file", options.SqlFile)
+	}
+
+	if err = p.parse(string(b)); err != nil {
+		return nil, fmt.Errorf("%s: %w", options.SqlFile, err)
+	}
+
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		return t.filterTables(ctx, p.tables, filter, options.Exclude)
+	})
+
+	if options.Verbose && err == nil {
+		l.Println("parse completed, table count:", len(tables))
+	}
+
+	return
+}
+
+// filterTables tables matching LIKE pattern of filter, tables are copied since prefix is of the filter.
+// Exclude may be schema qualified, tables of the same name in different schemas are reported unless excluded
+func (t *ddlFile) filterTables(ctx context.Context, parsed []*Table, filter *Filter, exclude []string) (tables []*Table, err error) {
+	var pattern *regexp.Regexp
+	if filter != nil {
+		l.Println("filter table_name like", filter.TableNamePattern)
+		pattern = likeRegexp(filter.TableNamePattern)
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	tables = make([]*Table, 0, len(parsed))
+	schemas := make(map[string]string, len(parsed))
+	for _, it := range parsed {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if excluded[it.Name] || excluded[it.Schema+"."+it.Name] || pattern != nil && !pattern.MatchString(it.Name) {
+			continue
+		}
+		if schema, ok := schemas[it.Name]; ok {
+			return nil, fmt.Errorf("table %s of schemas %q and %q, exclude one as schema.table", it.Name, schema, it.Schema)
+		}
+		schemas[it.Name] = it.Schema
+
+		tb := *it
+		if filter != nil {
+			tb.Prefix = filter.TablePrefix
+		}
+		tables = append(tables, &tb)
+	}
+	return
+}
+
+// likeRegexp regexp of sql LIKE pattern, case insensitive as default mysql collations
+func likeRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; {
+		case ch == '%':
+			b.WriteString(".*")
+		case ch == '_':
+			b.WriteString(".")
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+const (
+	// ddlWord keyword, unquoted identifier or number
+	ddlWord = 'w'
+	// ddlIdent quoted identifier
+	ddlIdent  = 'i'
+	ddlString = 's'
+	ddlPunct  = 'p'
+	// ddlEnd end of statement
+	ddlEnd = ';'
+)
+
+// ddlToken token of sql text, text of quoted identifiers and strings is unquoted,
+// start and end are offsets of the token in the text
+type ddlToken struct {
+	kind       byte
+	text       string
+	start, end int
+}
+
+var ddlDollarQuoteRegexp = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+func isDDLWordByte(ch byte) bool {
+	return ch == '_' || ch == '$' || ch >= 0x80 ||
+		ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// ddlError error at offset pos of src with line number
+func ddlError(src string, pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", strings.Count(src[:pos], "\n")+1, fmt.Sprintf(format, args...))
+}
+
+// lexDDL tokens of sql text, comments are skipped, `DELIMITER` command of mysql client is respected
+func lexDDL(src string, mysql bool) ([]ddlToken, error) {
+	tokens := make([]ddlToken, 0, len(src)/4)
+	delimiter := ";"
+	lineStart := true
+	for i := 0; i < len(src); {
+		ch := src[i]
+		if ch == '\n' {
+			lineStart = true
+			i++
+			continue
+		}
+		if ch == ' ' || ch == '\t' || ch == '\r' {
+			i++
+			continue
+		}
+
+		atLineStart := lineStart
+		lineStart = false
+		switch {
+		case mysql && atLineStart && len(src)-i > len("delimiter ") && strings.EqualFold(src[i:i+len("delimiter ")], "delimiter "):
+			end := ddlLineEnd(src, i)
+			delimiter = strings.TrimSpace(src[i+len("delimiter ") : end])
+			tokens = append(tokens, ddlToken{kind: ddlEnd, start: i, end: end})
+			i = end
+		case strings.HasPrefix(src[i:], delimiter):
+			tokens = append(tokens, ddlToken{kind: ddlEnd, text: delimiter, start: i, end: i + len(delimiter)})
+			i += len(delimiter)
+		case strings.HasPrefix(src[i:], "--") || mysql && ch == '#':
+			i = ddlLineEnd(src, i)
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, ddlError(src, i, "unterminated comment")
+			}
+			i += end + 4
+		case ch == '\'' || ch == '"' || ch == '`':
+			// double quoted is string of mysql, identifier of postgresql
+			kind := byte(ddlIdent)
+			if ch == '\'' || mysql && ch == '"' {
+				kind = ddlString
+			}
+			text, end, ok := ddlUnquote(src, i, mysql && kind == ddlString)
+			if !ok {
+				return nil, ddlError(src, i, "unterminated quote %c", ch)
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: text, start: i, end: end})
+			i = end
+		case ch == '$' && ddlDollarQuoteRegexp.MatchString(src[i:]):
+			tag := ddlDollarQuoteRegexp.FindString(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, ddlError(src, i, "unterminated quote %s", tag)
+			}
+			text := src[i+len(tag) : i+len(tag)+end]
+			tokens = append(tokens, ddlToken{kind: ddlString, text: text, start: i, end: i + len(tag)*2 + end})
+			i += len(tag)*2 + end
+		case isDDLWordByte(ch):
+			end := i + 1
+			for end < len(src) && isDDLWordByte(src[end]) {
+				end++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlWord, text: src[i:end], start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, ddlToken{kind: ddlPunct, text: src[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func ddlLineEnd(src string, i int) int {
+	if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(src)
+}
+
+// ddlUnquote unquote quoted text at src[i], doubled quote is the quote itself, backslash escapes of mysql strings
+func ddlUnquote(src string, i int, backslash bool) (text string, end int, ok bool) {
+	quote := src[i]
+	var b strings.Builder
+	for j := i + 1; j < len(src); j++ {
+		ch := src[j]
+		switch {
+		case backslash && ch == '\\' && j+1 < len(src):
+			j++
+			switch src[j] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(src[j])
+			}
+		case ch == quote && j+1 < len(src) && src[j+1] == quote:
+			b.WriteByte(quote)
+			j++
+		case ch == quote:
+			return b.String(), j + 1, true
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return "", 0, false
+}
+
+// ddlParser tables of CREATE TABLE, ALTER TABLE, CREATE INDEX and COMMENT ON statements,
+// enum types of postgresql CREATE TYPE statements
+type ddlParser struct {
+	src    string
+	mysql  bool
+	tables []*Table
+	// byName tables keyed by schema qualified name, see ddlKey
+	byName map[string]*Table
+	enums  map[string][]string
+	// searchPath schemas of unqualified names, `SET search_path` of postgresql, `USE` database of mysql
+	searchPath []string
+	// refs qualified name parts of referenced tables of foreign keys, resolved after all tables are parsed
+	refs map[*ForeignKey][]string
+}
+
+// ddlKey key of table in schema, schema is empty for mysql files without `USE`
+func ddlKey(schema, name string) string {
+	return schema + "." + name
+}
+
+// ddlStmt tokens of a statement, or of an item of a statement, with read position
+type ddlStmt struct {
+	tokens []ddlToken
+	i      int
+}
+
+func (s *ddlStmt) done() bool {
+	return s.i >= len(s.tokens)
+}
+
+// peek returns true if next tokens are the keywords or punctuations, case insensitive
+func (s *ddlStmt) peek(words ...string) bool {
+	if s.i+len(words) > len(s.tokens) {
+		return false
+	}
+	for j, w := range words {
+		tok := s.tokens[s.i+j]
+		if tok.kind != ddlWord && tok.kind != ddlPunct || !strings.EqualFold(tok.text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept consume next tokens if they are the keywords
+func (s *ddlStmt) accept(words ...string) bool {
+	if !s.peek(words...) {
+		return false
+	}
+	s.i += len(words)
+	return true
+}
+
+func (s *ddlStmt) next() (tok ddlToken, ok bool) {
+	if s.done() {
+		return
+	}
+	s.i++
+	return s.tokens[s.i-1], true
+}
+
+// group consume tokens of parentheses, returns tokens inside
+func (s *ddlStmt) group() ([]ddlToken, bool) {
+	if !s.peek("(") {
+		return nil, false
+	}
+	depth := 0
+	for j := s.i; j < len(s.tokens); j++ {
+		switch {
+		case s.tokens[j].kind != ddlPunct:
+		case s.tokens[j].text == "(":
+			depth++
+		case s.tokens[j].text == ")":
+			depth--
+			if depth == 0 {
+				inside := s.tokens[s.i+1 : j]
+				s.i = j + 1
+				return inside, true
+			}
+		}
+	}
+	inside := s.tokens[s.i+1:]
+	s.i = len(s.tokens)
+	return inside, true
+}
+
+// until consume tokens until one of the keywords out of parentheses
+func (s *ddlStmt) until(keywords [][]string) []ddlToken {
+	start := s.i
+	for !s.done() && !s.peekAny(keywords) {
+		if _, ok := s.group(); !ok {
+			s.i++
+		}
+	}
+	return s.tokens[start:s.i]
+}
+
+func (s *ddlStmt) peekAny(keywords [][]string) bool {
+	for _, words := range keywords {
+		if s.peek(words...) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitDDL split tokens by commas out of parentheses
+func splitDDL(tokens []ddlToken) [][]ddlToken {
+	items := make([][]ddlToken, 0, 8)
+	depth, start := 0, 0
+	for j, tok := range tokens {
+		if tok.kind != ddlPunct {
+			continue
+		}
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				items = append(items, tokens[start:j])
+				start = j + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+	return items
+}
+
+// text source text of tokens
+func (p *ddlParser) text(tokens []ddlToken) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return p.src[tokens[0].start:tokens[len(tokens)-1].end]
+}
+
+// ident name of identifier token, unquoted identifiers of postgresql are folded to lower case
+func (p *ddlParser) ident(tok ddlToken) string {
+	if tok.kind == ddlWord && !p.mysql {
+		return strings.ToLower(tok.text)
+	}
+	return tok.text
+}
+
+// name parts of a qualified name, e.g. public.user
+func (p *ddlParser) name(s *ddlStmt) []string {
+	parts := make([]string, 0, 2)
+	for !s.done() {
+		tok := s.tokens[s.i]
+		if tok.kind != ddlWord && tok.kind != ddlIdent {
+			break
+		}
+		s.i++
+		parts = append(parts, p.ident(tok))
+		if !s.accept(".") {
+			break
+		}
+	}
+	return parts
+}
+
+// table parsed table of qualified name, unqualified name is resolved by search path, nil if not created in the file
+func (p *ddlParser) table(parts []string) *Table {
+	switch len(parts) {
+	case 0:
+		return nil
+	case 1:
+		for _, schema := range p.searchPath {
+			if table, ok := p.byName[ddlKey(schema, parts[0])]; ok {
+				return table
+			}
+		}
+		return nil
+	}
+	return p.byName[ddlKey(parts[len(parts)-2], parts[len(parts)-1])]
+}
+
+// schema schema of created table of qualified name, the first of search path if unqualified
+func (p *ddlParser) schema(parts []string) string {
+	if len(parts) > 1 {
+		return parts[len(parts)-2]
+	}
+	if len(p.searchPath) > 0 {
+		return p.searchPath[0]
+	}
+	return ""
+}
+
+func ddlField(table *Table, name string) *Field {
+	for _, f := range table.Fields {
+		if strings.EqualFold(f.Field, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (p *ddlParser) parse(src string) error {
+	p.src = src
+	tokens, err := lexDDL(src, p.mysql)
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	for j := 0; j <= len(tokens); j++ {
+		if j < len(tokens) && tokens[j].kind != ddlEnd {
+			continue
+		}
+		if j > start {
+			if err = p.statement(tokens[start:j]); err != nil {
+				return err
+			}
+		}
+		start = j + 1
+	}
+	p.resolve()
+	return nil
+}
+
+// statement parse statement of tables, others are ignored
+func (p *ddlParser) statement(tokens []ddlToken) error {
+	s := &ddlStmt{tokens: tokens}
+	switch {
+	case s.accept("create"):
+		s.accept("or", "replace")
+		switch {
+		case s.accept("type"):
+			p.createType(s)
+			return nil
+		case s.accept("unique", "index"):
+			p.createIndex(s, true)
+			return nil
+		case s.accept("index"):
+			p.createIndex(s, false)
+			return nil
+		}
+		for s.accept("temporary") || s.accept("temp") || s.accept("unlogged") || s.accept("global") || s.accept("local") {
+		}
+		if s.accept("table") {
+			return p.createTable(s)
+		}
+	case s.accept("alter", "table"):
+		return p.alterTable(s)
+	case s.accept("drop", "table"):
+		p.dropTable(s)
+	case s.accept("set", "search_path"):
+		p.setSearchPath(s)
+	case p.mysql && s.accept("use"):
+		if parts := p.name(s); len(parts) == 1 {
+			p.searchPath = []string{parts[0]}
+		}
+	case s.accept("comment", "on"):
+		p.commentOn(s)
+	}
+	return nil
+}
+
+func (p *ddlParser) createTable(s *ddlStmt) error {
+	s.accept("if", "not", "exists")
+	parts := p.name(s)
+	if len(parts) == 0 {
+		return ddlError(p.src, s.tokens[0].start, "missing table name")
+	}
+	body, ok := s.group()
+	if !ok {
+		// CREATE TABLE ... AS SELECT, or LIKE of mysql
+		return nil
+	}
+
+	table := &Table{Name: parts[len(parts)-1], Schema: p.schema(parts), Ddl: p.text(s.tokens)}
+	if !p.mysql {
+		table.Ddl += ";"
+	}
+
+	// constraints refer columns, columns are parsed first
+	constraints := make([]*ddlStmt, 0, 4)
+	for _, item := range splitDDL(body) {
+		it := &ddlStmt{tokens: item}
+		if p.isConstraint(it) {
+			constraints = append(constraints, it)
+			continue
+		}
+		if err := p.column(table, it); err != nil {
+			return err
+		}
+	}
+	for _, it := range constraints {
+		p.constraint(table, it)
+	}
+
+	// table options of mysql, e.g. ENGINE=InnoDB COMMENT='user'
+	for !s.done() {
+		if s.accept("comment") {
+			s.accept("=")
+			if tok, ok := s.next(); ok && tok.kind == ddlString {
+				table.Comment = tok.text
+			}
+			continue
+		}
+		s.i++
+	}
+
+	key := ddlKey(table.Schema, table.Name)
+	if _, ok := p.byName[key]; ok {
+		return ddlError(p.src, s.tokens[0].start, "duplicate table %s", strings.TrimPrefix(key, "."))
+	}
+	p.tables = append(p.tables, table)
+	p.byName[key] = table
+	return nil
+}
+
+// dropTable forget dropped tables, e.g. DROP TABLE IF EXISTS of mysqldump before CREATE TABLE
+func (p *ddlParser) dropTable(s *ddlStmt) {
+	s.accept("if", "exists")
+	for {
+		if table := p.table(p.name(s)); table != nil {
+			for key, it := range p.byName {
+				if it == table {
+					delete(p.byName, key)
+				}
+			}
+			for j, it := range p.tables {
+				if it == table {
+					p.tables = append(p.tables[:j], p.tables[j+1:]...)
+					break
+				}
+			}
+		}
+		if !s.accept(",") {
+			return
+		}
+	}
+}
+
+// setSearchPath schemas of `SET search_path TO a, b` or `= a, b`, empty search path of pg_dump is ignored
+// as its names are qualified
+func (p *ddlParser) setSearchPath(s *ddlStmt) {
+	if !s.accept("to") && !s.accept("=") {
+		return
+	}
+	schemas := make([]string, 0, 2)
+	for _, item := range splitDDL(s.tokens[s.i:]) {
+		if len(item) != 1 {
+			continue
+		}
+		switch tok := item[0]; tok.kind {
+		case ddlWord, ddlIdent:
+			schemas = append(schemas, p.ident(tok))
+		case ddlString:
+			if tok.text != "" {
+				schemas = append(schemas, tok.text)
+			}
+		}
+	}
+	if len(schemas) > 0 {
+		p.searchPath = schemas
+	}
+}
+
+// ddlColumnKeywords keywords after column type
+var ddlColumnKeywords = [][]string{
+	{"not"}, {"null"}, {"default"}, {"primary"}, {"unique"}, {"key"}, {"auto_increment"}, {"comment"},
+	{"references"}, {"check"}, {"generated"}, {"as"}, {"collate"}, {"constraint"}, {"on"}, {"character", "set"},
+	{"charset"}, {"visible"}, {"invisible"}, {"stored"}, {"virtual"}, {"srid"},
+}
+
+func (p *ddlParser) column(table *Table, s *ddlStmt) error {
+	tok, ok := s.next()
+	if !ok {
+		return nil
+	}
+	if tok.kind != ddlWord && tok.kind != ddlIdent {
+		return ddlError(p.src, tok.start, "table %s: unexpected %q", table.Name, tok.text)
+	}
+	f := &Field{Field: p.ident(tok), Nullable: true}
+
+	typeTokens := s.until(ddlColumnKeywords)
+	if len(typeTokens) == 0 {
+		return ddlError(p.src, tok.start, "table %s: missing type of column %s", table.Name, f.Field)
+	}
+	var serial bool
+	f.Type, serial = p.columnType(typeTokens)
+	if serial {
+		f.AutoIncrement = true
+		f.Nullable = false
+		f.Sequence = fmt.Sprint(table.Name, "_", f.Field, "_seq")
+		if table.Schema != "" {
+			f.Sequence = table.Schema + "." + f.Sequence
+		}
+		f.Default = fmt.Sprintf("nextval('%s'::regclass)", f.Sequence)
+	}
+
+	var constraintName string
+	for !s.done() {
+		switch {
+		case s.accept("not", "null"):
+			f.Nullable = false
+		case s.accept("null"):
+			f.Nullable = true
+		case s.accept("default"):
+			p.setDefault(f, s.until(ddlColumnKeywords))
+		case s.accept("auto_increment"):
+			f.AutoIncrement = true
+			f.Extra = strings.TrimSpace(f.Extra + " auto_increment")
+		case s.accept("on", "update"):
+			f.Extra = strings.TrimSpace(f.Extra + " on update " + p.text(s.until(ddlColumnKeywords)))
+		case s.accept("primary", "key") || p.mysql && s.accept("key"):
+			f.Key = "PRI"
+			f.Nullable = false
+		case s.accept("unique"):
+			s.accept("key")
+			if f.Key != "PRI" {
+				f.Key = "UNI"
+			}
+		case s.accept("comment"):
+			if tok, ok := s.next(); ok && tok.kind == ddlString {
+				f.Comment = tok.text
+			}
+		case s.accept("collate"):
+			if tok, ok := s.next(); ok {
+				f.Collation = tok.text
+			}
+		case s.accept("character", "set") || s.accept("charset"):
+			s.next()
+		case s.accept("constraint"):
+			if tok, ok := s.next(); ok {
+				constraintName = p.ident(tok)
+			}
+		case s.accept("references"):
+			fk, refColumns := p.references(s)
+			if len(refColumns) <= 1 {
+				fk.Name = constraintName
+				if fk.Name == "" {
+					fk.Name = fmt.Sprint(table.Name, "_", f.Field, "_fkey")
+				}
+				fk.Column = f.Field
+				if len(refColumns) == 1 {
+					fk.RefColumn = refColumns[0]
+				}
+				table.ForeignKeys = append(table.ForeignKeys, fk)
+			}
+		case s.accept("check"):
+			if expr, ok := s.group(); ok {
+				p.setCheck(table, f, constraintName, expr)
+			}
+		case s.accept("generated", "always", "as", "identity") || s.accept("generated", "by", "default", "as", "identity"):
+			s.group()
+			f.AutoIncrement = true
+			f.Nullable = false
+			f.Extra = "identity"
+		case s.accept("generated", "always", "as") || s.accept("as"):
+			expr, _ := s.group()
+			f.Generated = p.text(expr)
+			if p.mysql {
+				f.Extra = "VIRTUAL GENERATED"
+				if s.peek("stored") {
+					f.Extra = "STORED GENERATED"
+				}
+			}
+		default:
+			if _, ok := s.group(); !ok {
+				s.i++
+			}
+		}
+	}
+
+	table.Fields = append(table.Fields, f)
+	return nil
+}
+
+// setDefault default of column, quoted string of mysql is unquoted as information_schema.columns
+func (p *ddlParser) setDefault(f *Field, expr []ddlToken) {
+	switch {
+	case len(expr) == 1 && expr[0].kind == ddlWord && strings.EqualFold(expr[0].text, "null"):
+		f.Default = ""
+	case len(expr) == 1 && expr[0].kind == ddlString && p.mysql:
+		f.Default = expr[0].text
+	default:
+		f.Default = p.text(expr)
+	}
+
+	// serial column of pg_dump defaults to nextval of its sequence
+	if m := ddlNextvalRegexp.FindStringSubmatch(f.Default); m != nil {
+		f.AutoIncrement = true
+		f.Sequence = m[1]
+	}
+}
+
+var ddlNextvalRegexp = regexp.MustCompile(`^nextval\('([^']+)'`)
+
+// ddlMysqlTypes aliases of mysql column types
+var ddlMysqlTypes = [][]string{
+	{"integer", "int"},
+	{"boolean", "tinyint(1)"},
+	{"bool", "tinyint(1)"},
+	{"numeric", "decimal"},
+	{"dec", "decimal"},
+	{"double precision", "double"},
+	{"real", "double"},
+}
+
+// ddlPostgresqlTypes aliases of postgresql column types, names are of format_type
+var ddlPostgresqlTypes = [][]string{
+	{"int", "integer"},
+	{"int4", "integer"},
+	{"int2", "smallint"},
+	{"int8", "bigint"},
+	{"bool", "boolean"},
+	{"float4", "real"},
+	{"float8", "double precision"},
+	{"float", "double precision"},
+	{"decimal", "numeric"},
+	{"varchar", "character varying"},
+	{"char", "character"},
+	{"bpchar", "character"},
+	{"varbit", "bit varying"},
+}
+
+// ddlSerialTypes integer types of postgresql serial types
+var ddlSerialTypes = map[string]string{
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+}
+
+// columnType type of column tokens as introspected, e.g. `decimal (12, 2)` -> decimal(12,2), serial is
+// integer of postgresql
+func (p *ddlParser) columnType(tokens []ddlToken) (dbType string, serial bool) {
+	var b strings.Builder
+	for j, tok := range tokens {
+		text := tok.text
+		switch tok.kind {
+		case ddlWord:
+			text = strings.ToLower(text)
+		case ddlString:
+			text = p.text(tokens[j : j+1])
+		}
+		if j > 0 && tok.kind != ddlPunct && (tokens[j-1].kind != ddlPunct || tokens[j-1].text == ")") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+	dbType = b.String()
+
+	if p.mysql {
+		return ddlAlias(ddlMysqlTypes, dbType), false
+	}
+
+	if v, ok := ddlSerialTypes[dbType]; ok {
+		return v, true
+	}
+	dbType = ddlAlias(ddlPostgresqlTypes, dbType)
+	for _, tz := range []string{"timestamptz", "timetz"} {
+		if dbType == tz || strings.HasPrefix(dbType, tz+"(") {
+			dbType = strings.TrimSuffix(tz, "tz") + dbType[len(tz):] + " with time zone"
+		}
+	}
+	for _, name := range []string{"timestamp", "time"} {
+		if (dbType == name || strings.HasPrefix(dbType, name+"(")) && !strings.HasSuffix(dbType, " time zone") {
+			dbType += " without time zone"
+		}
+	}
+	return dbType, false
+}
+
+// ddlAlias replace alias name of type, e.g. int(11) -> integer(11)
+func ddlAlias(aliases [][]string, dbType string) string {
+	for _, alias := range aliases {
+		name := alias[0]
+		if dbType == name || strings.HasPrefix(dbType, name) && strings.ContainsAny(dbType[len(name):len(name)+1], "( [") {
+			return alias[1] + dbType[len(name):]
+		}
+	}
+	return dbType
+}
+
+// isConstraint returns true if table item is a constraint or an index
+func (p *ddlParser) isConstraint(s *ddlStmt) bool {
+	for _, w := range []string{"constraint", "primary", "unique", "foreign", "check", "like", "exclude"} {
+		if s.peek(w) {
+			return true
+		}
+	}
+	return p.mysql && (s.peek("key") || s.peek("index") || s.peek("fulltext") || s.peek("spatial"))
+}
+
+// constraint apply table constraint to columns, composite keys are PRI for all columns of primary key,
+// MUL for the first column of others as mysql column_key
+func (p *ddlParser) constraint(table *Table, s *ddlStmt) {
+	var name string
+	if s.accept("constraint") && !p.isConstraint(s) {
+		if tok, ok := s.next(); ok {
+			name = p.ident(tok)
+		}
+	}
+
+	switch {
+	case s.accept("primary", "key"):
+		for _, column := range p.keyColumns(s) {
+			if f := ddlField(table, column); f != nil {
+				f.Key = "PRI"
+				f.Nullable = false
+			}
+		}
+	case s.accept("unique"):
+		p.uniqueKey(table, p.keyColumns(s))
+	case s.accept("foreign", "key"):
+		columns := p.keyColumns(s)
+		if !s.accept("references") {
+			return
+		}
+		fk, refColumns := p.references(s)
+		if len(columns) != 1 || len(refColumns) > 1 {
+			return
+		}
+		fk.Name = name
+		if fk.Name == "" {
+			fk.Name = fmt.Sprint(table.Name, "_", columns[0], "_fkey")
+		}
+		if f := ddlField(table, columns[0]); f != nil {
+			fk.Column = f.Field
+		}
+		if len(refColumns) == 1 {
+			fk.RefColumn = refColumns[0]
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	case s.accept("check"):
+		if expr, ok := s.group(); ok {
+			p.setCheck(table, nil, name, expr)
+		}
+	case p.mysql && (s.accept("key") || s.accept("index") || s.accept("fulltext") || s.accept("spatial")):
+		p.indexKey(table, p.keyColumns(s))
+	}
+}
+
+func (p *ddlParser) uniqueKey(table *Table, columns []string) {
+	if len(columns) != 1 {
+		p.indexKey(table, columns)
+		return
+	}
+	if f := ddlField(table, columns[0]); f != nil && f.Key != "PRI" {
+		f.Key = "UNI"
+	}
+}
+
+// indexKey first column of index is MUL of mysql
+func (p *ddlParser) indexKey(table *Table, columns []string) {
+	if !p.mysql || len(columns) == 0 {
+		return
+	}
+	if f := ddlField(table, columns[0]); f != nil && f.Key == "" {
+		f.Key = "MUL"
+	}
+}
+
+// keyColumns columns of key, tokens before parentheses are skipped, e.g. index name, USING BTREE,
+// expression is an empty column
+func (p *ddlParser) keyColumns(s *ddlStmt) []string {
+	for !s.done() && !s.peek("(") {
+		s.i++
+	}
+	inside, _ := s.group()
+	items := splitDDL(inside)
+	columns := make([]string, 0, len(items))
+	for _, item := range items {
+		if len(item) > 0 && (item[0].kind == ddlWord || item[0].kind == ddlIdent) {
+			columns = append(columns, p.ident(item[0]))
+		} else {
+			columns = append(columns, "")
+		}
+	}
+	return columns
+}
+
+// references foreign key of REFERENCES clause, referenced column is resolved to primary key if omitted
+func (p *ddlParser) references(s *ddlStmt) (*ForeignKey, []string) {
+	fk := &ForeignKey{OnDelete: "NO ACTION", OnUpdate: "NO ACTION"}
+	if parts := p.name(s); len(parts) > 0 {
+		fk.RefTable = parts[len(parts)-1]
+		p.refs[fk] = parts
+	}
+	var refColumns []string
+	if s.peek("(") {
+		refColumns = p.keyColumns(s)
+	}
+
+	for {
+		switch {
+		case s.accept("on", "delete"):
+			fk.OnDelete = p.action(s)
+		case s.accept("on", "update"):
+			fk.OnUpdate = p.action(s)
+		case s.accept("match"):
+			s.next()
+		case s.accept("deferrable") || s.accept("not", "deferrable"):
+		case s.accept("initially"):
+			s.next()
+		default:
+			return fk, refColumns
+		}
+	}
+}
+
+// action referential action, e.g. CASCADE, SET NULL
+func (p *ddlParser) action(s *ddlStmt) string {
+	for _, words := range [][]string{{"cascade"}, {"restrict"}, {"no", "action"}, {"set", "null"}, {"set", "default"}} {
+		if s.accept(words...) {
+			return strings.ToUpper(strings.Join(words, " "))
+		}
+	}
+	return "NO ACTION"
+}
+
+// setCheck assign check constraint to field, or to the only column referred by a table check constraint
+func (p *ddlParser) setCheck(table *Table, f *Field, name string, expr []ddlToken) {
+	if f == nil {
+		for _, tok := range expr {
+			if tok.kind != ddlWord && tok.kind != ddlIdent {
+				continue
+			}
+			column := ddlField(table, tok.text)
+			if column == nil || column == f {
+				continue
+			}
+			if f != nil {
+				// refers more than one column
+				return
+			}
+			f = column
+		}
+		if f == nil {
+			return
+		}
+	}
+
+	f.CheckName = name
+	if f.CheckName == "" {
+		f.CheckName = fmt.Sprint(table.Name, "_", f.Field, "_check")
+	}
+	f.Check = strings.ReplaceAll(p.text(expr), "`", "")
+}
+
+// alterTable constraints, defaults and identities of ALTER TABLE, e.g. primary keys and foreign keys of pg_dump
+func (p *ddlParser) alterTable(s *ddlStmt) error {
+	s.accept("if", "exists")
+	s.accept("only")
+	table := p.table(p.name(s))
+	if table == nil {
+		return nil
+	}
+
+	for _, item := range splitDDL(s.tokens[s.i:]) {
+		it := &ddlStmt{tokens: item}
+		switch {
+		case it.accept("add"):
+			if p.isConstraint(it) {
+				p.constraint(table, it)
+				continue
+			}
+			it.accept("column")
+			it.accept("if", "not", "exists")
+			if err := p.column(table, it); err != nil {
+				return err
+			}
+		case it.accept("alter"):
+			it.accept("column")
+			tok, ok := it.next()
+			if !ok {
+				continue
+			}
+			f := ddlField(table, p.ident(tok))
+			if f == nil {
+				continue
+			}
+			switch {
+			case it.accept("set", "default"):
+				p.setDefault(f, it.tokens[it.i:])
+			case it.accept("drop", "default"):
+				f.Default = ""
+			case it.accept("set", "not", "null"):
+				f.Nullable = false
+			case it.accept("drop", "not", "null"):
+				f.Nullable = true
+			case it.accept("add", "generated"):
+				f.AutoIncrement = true
+				f.Nullable = false
+				f.Extra = "identity"
+			}
+		}
+	}
+	return nil
+}
+
+// createIndex unique index of a single column is UNI
+func (p *ddlParser) createIndex(s *ddlStmt, unique bool) {
+	s.accept("concurrently")
+	s.accept("if", "not", "exists")
+	if !s.peek("on") {
+		p.name(s)
+	}
+	if !s.accept("on") {
+		return
+	}
+	s.accept("only")
+	table := p.table(p.name(s))
+	if table == nil {
+		return
+	}
+
+	columns := p.keyColumns(s)
+	if unique {
+		p.uniqueKey(table, columns)
+	} else {
+		p.indexKey(table, columns)
+	}
+}
+
+// commentOn comments of postgresql, e.g. COMMENT ON COLUMN public.user.id IS 'id'
+func (p *ddlParser) commentOn(s *ddlStmt) {
+	isTable := s.accept("table")
+	if !isTable && !s.accept("column") {
+		return
+	}
+	parts := p.name(s)
+	if !s.accept("is") {
+		return
+	}
+	tok, _ := s.next()
+	comment := ""
+	if tok.kind == ddlString {
+		comment = tok.text
+	}
+
+	if isTable {
+		if table := p.table(parts); table != nil {
+			table.Comment = comment
+		}
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+	if table := p.table(parts[:len(parts)-1]); table != nil {
+		if f := ddlField(table, parts[len(parts)-1]); f != nil {
+			f.Comment = comment
+		}
+	}
+}
+
+// createType values of postgresql enum type, e.g. CREATE TYPE mood AS ENUM ('sad', 'happy')
+func (p *ddlParser) createType(s *ddlStmt) {
+	parts := p.name(s)
+	if len(parts) == 0 || !s.accept("as", "enum") {
+		return
+	}
+	inside, _ := s.group()
+	values := make([]string, 0, len(inside))
+	for _, tok := range inside {
+		if tok.kind == ddlString {
+			values = append(values, tok.text)
+		}
+	}
+	p.enums[parts[len(parts)-1]] = values
+}
+
+// resolve go types of fields, enum values, referenced primary keys of foreign keys
+func (p *ddlParser) resolve() {
+	getGoType := new(postgresql).getGoType
+	if p.mysql {
+		getGoType = new(mysql).getGoType
+	}
+
+	for _, table := range p.tables {
+		for _, f := range table.Fields {
+			f.Null = "NO"
+			if f.Nullable {
+				f.Null = "YES"
+			}
+
+			if values, ok := p.enums[f.Type[strings.LastIndex(f.Type, ".")+1:]]; ok && !p.mysql {
+				f.GoType = "string"
+				f.EnumValues = values
+				continue
+			}
+
+			f.GoType, _ = getGoType(f.Type)
+			if p.mysql {
+				f.EnumValues = parseEnumValues(f.Type)
+			}
+		}
+
+		for _, fk := range table.ForeignKeys {
+			if fk.RefColumn != "" {
+				continue
+			}
+			if ref := p.table(p.refs[fk]); ref != nil {
+				if pks := primaryKeys(ref.Fields); len(pks) == 1 {
+					fk.RefColumn = pks[0].Field
+				}
+			}
+		}
+	}
+}
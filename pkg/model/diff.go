@@ -0,0 +1,246 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// schemaDiff changes of tables since previous snapshot
+type schemaDiff struct {
+	Added    []*Table
+	Removed  []*Table
+	Modified []*tableDiff
+}
+
+type tableDiff struct {
+	Name     string
+	Comment  []string
+	Added    []*Field
+	Removed  []*Field
+	Modified []*fieldDiff
+	// Reordered columns of both snapshots whose ordinal changed, e.g. `email` 2 -> 3
+	Reordered []string
+}
+
+type fieldDiff struct {
+	Field   string
+	Changes []string
+}
+
+func (d *schemaDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+func diffTables(previous, current []*Table) *schemaDiff {
+	d := new(schemaDiff)
+	prev := make(map[string]*Table, len(previous))
+	for _, t := range previous {
+		prev[t.Name] = t
+	}
+	cur := make(map[string]bool, len(current))
+
+	for _, t := range current {
+		cur[t.Name] = true
+		old, ok := prev[t.Name]
+		if !ok {
+			d.Added = append(d.Added, t)
+			continue
+		}
+		if td := diffTable(old, t); td != nil {
+			d.Modified = append(d.Modified, td)
+		}
+	}
+
+	for _, t := range previous {
+		if !cur[t.Name] {
+			d.Removed = append(d.Removed, t)
+		}
+	}
+
+	return d
+}
+
+func diffTable(previous, current *Table) *tableDiff {
+	td := &tableDiff{Name: current.Name}
+	if previous.Comment != current.Comment {
+		td.Comment = []string{previous.Comment, current.Comment}
+	}
+
+	prev := make(map[string]*Field, len(previous.Fields))
+	for _, f := range previous.Fields {
+		prev[f.Field] = f
+	}
+	cur := make(map[string]bool, len(current.Fields))
+
+	for _, f := range current.Fields {
+		cur[f.Field] = true
+		old, ok := prev[f.Field]
+		if !ok {
+			td.Added = append(td.Added, f)
+			continue
+		}
+		if fd := diffField(old, f); fd != nil {
+			td.Modified = append(td.Modified, fd)
+		}
+	}
+
+	for _, f := range previous.Fields {
+		if !cur[f.Field] {
+			td.Removed = append(td.Removed, f)
+		}
+	}
+
+	td.Reordered = reorderedColumns(previous, current)
+
+	if td.Comment == nil && len(td.Added) == 0 && len(td.Removed) == 0 && len(td.Modified) == 0 && len(td.Reordered) == 0 {
+		return nil
+	}
+	return td
+}
+
+// reorderedColumns columns of both tables whose 1-based ordinal changed
+func reorderedColumns(previous, current *Table) []string {
+	ordinals := make(map[string]int, len(previous.Fields))
+	for i, f := range previous.Fields {
+		ordinals[f.Field] = i + 1
+	}
+
+	var reordered []string
+	for i, f := range current.Fields {
+		if o, ok := ordinals[f.Field]; ok && o != i+1 {
+			reordered = append(reordered, fmt.Sprintf("`%s` %d -> %d", f.Field, o, i+1))
+		}
+	}
+	return reordered
+}
+
+func diffField(previous, current *Field) *fieldDiff {
+	fd := &fieldDiff{Field: current.Field}
+	change := func(name, from, to string) {
+		if from != to {
+			fd.Changes = append(fd.Changes, fmt.Sprintf("%s `%s` -> `%s`", name, from, to))
+		}
+	}
+	change("type", previous.Type, current.Type)
+	change("null", previous.Null, current.Null)
+	change("key", previous.Key, current.Key)
+	change("default", previous.Default, current.Default)
+	change("comment", OneLine(previous.Comment), OneLine(current.Comment))
+
+	if len(fd.Changes) == 0 {
+		return nil
+	}
+	return fd
+}
+
+// markdown human readable summary of the diff
+func (d *schemaDiff) markdown() string {
+	var b strings.Builder
+	b.WriteString("# schema changelog\n\n")
+	fmt.Fprintf(&b, "generated by database-struct @%v\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	if d.empty() {
+		b.WriteString("\nno changes\n")
+		return b.String()
+	}
+
+	if len(d.Added) > 0 {
+		b.WriteString("\n## added tables\n\n")
+		for _, t := range d.Added {
+			fmt.Fprintf(&b, "- `%s` %s\n", t.Name, OneLine(t.Comment))
+		}
+	}
+
+	if len(d.Removed) > 0 {
+		b.WriteString("\n## removed tables\n\n")
+		for _, t := range d.Removed {
+			fmt.Fprintf(&b, "- `%s`\n", t.Name)
+		}
+	}
+
+	if len(d.Modified) > 0 {
+		b.WriteString("\n## modified tables\n")
+		for _, td := range d.Modified {
+			fmt.Fprintf(&b, "\n### `%s`\n\n", td.Name)
+			if td.Comment != nil {
+				fmt.Fprintf(&b, "- comment `%s` -> `%s`\n", OneLine(td.Comment[0]), OneLine(td.Comment[1]))
+			}
+			for _, f := range td.Added {
+				fmt.Fprintf(&b, "- added column `%s` `%s`\n", f.Field, f.Type)
+			}
+			for _, f := range td.Removed {
+				fmt.Fprintf(&b, "- removed column `%s`\n", f.Field)
+			}
+			for _, fd := range td.Modified {
+				fmt.Fprintf(&b, "- modified column `%s`: %s\n", fd.Field, strings.Join(fd.Changes, ", "))
+			}
+			if len(td.Reordered) > 0 {
+				fmt.Fprintf(&b, "- reordered columns: %s\n", strings.Join(td.Reordered, ", "))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// writeChangeLog compare tables with previous snapshot, write changelog and update the snapshot
+func writeChangeLog(options *Options, tables []*Table) error {
+	snapshot := snapshotFile(options)
+	previous, err := LoadTables(snapshot)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	d := diffTables(previous, tables)
+	if options.Verbose {
+		l.Printf("schema changes, added: %d, removed: %d, modified: %d", len(d.Added), len(d.Removed), len(d.Modified))
+	}
+
+	err = ioutil.WriteFile(options.ChangeLogFile, []byte(d.markdown()), 0600)
+	if err != nil {
+		return err
+	}
+	return DumpTables(snapshot, tables)
+}
+
+// snapshotFile options.SnapshotFile, default schema.json beside the changelog
+func snapshotFile(options *Options) string {
+	if options.SnapshotFile != "" || options.ChangeLogFile == "" {
+		return options.SnapshotFile
+	}
+	return filepath.Join(filepath.Dir(options.ChangeLogFile), "schema.json")
+}
+
+// warnColumnReorders log columns whose ordinal changed since the previous snapshot, outputs depending on
+// column order, e.g. proto field numbers and scan destinations, change silently otherwise
+func warnColumnReorders(options *Options, tables []*Table) error {
+	snapshot := snapshotFile(options)
+	if snapshot == "" {
+		return nil
+	}
+
+	previous, err := LoadTables(snapshot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	prev := make(map[string]*Table, len(previous))
+	for _, t := range previous {
+		prev[t.Name] = t
+	}
+	for _, t := range tables {
+		if old, ok := prev[t.Name]; ok {
+			if reordered := reorderedColumns(old, t); len(reordered) > 0 {
+				l.Printf("warning: column order of table %s changed: %s", t.Name, strings.Join(reordered, ", "))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// embedGroup columns sharing a prefix folded into an embedded struct, e.g. address_city -> Address.City
+type embedGroup struct {
+	Prefix string
+	Name   string
+	// fields columns with prefix trimmed, generated in the embedded struct
+	fields []*Field
+	table  *Table
+}
+
+// resolveEmbedGroups fold columns of options.EmbedPrefixGroups into shared embedded structs,
+// the struct is generated from the first table, tables with different columns keep the columns.
+// Struct name is suffixed with Fields if it's the name of a model or the base struct, e.g. AddressFields
+func resolveEmbedGroups(options *Options, tables []*Table) []*embedGroup {
+	used := make(map[string]bool, len(tables)+1)
+	for _, table := range tables {
+		used[goStructName(table)] = true
+		for _, f := range table.Fields {
+			f.embed = nil
+		}
+	}
+	if options.BaseStruct != "" {
+		used[options.BaseStruct] = true
+	}
+
+	groups := make([]*embedGroup, 0, len(options.EmbedPrefixGroups))
+	for _, prefix := range options.EmbedPrefixGroups {
+		var group *embedGroup
+		for _, table := range tables {
+			fields := make([]*Field, 0, 4)
+			for _, f := range table.Fields {
+				if strings.HasPrefix(f.Field, prefix) && len(f.Field) > len(prefix) {
+					fields = append(fields, f)
+				}
+			}
+			if len(fields) < 2 {
+				continue
+			}
+
+			if group == nil {
+				group = &embedGroup{
+					Prefix: prefix,
+					Name:   embedName(used, TitleCase(prefix)),
+					table:  table,
+				}
+				used[group.Name] = true
+				for _, f := range fields {
+					it := *f
+					it.Field = strings.TrimPrefix(f.Field, prefix)
+					it.goName = ""
+					it.column = f
+					group.fields = append(group.fields, &it)
+				}
+				groups = append(groups, group)
+			} else if !group.match(options, fields) {
+				continue
+			}
+
+			for _, f := range fields {
+				f.embed = group
+			}
+		}
+	}
+	return groups
+}
+
+// embedName name of embedded struct not in used, e.g. AddressFields if Address is a model, AddressFields2 then
+func embedName(used map[string]bool, name string) string {
+	if !used[name] {
+		return name
+	}
+	name += "Fields"
+	for i := 2; used[name]; i++ {
+		name = strings.TrimRight(name, "0123456789") + strconv.Itoa(i)
+	}
+	return name
+}
+
+// embeddedColumn column name of f in the embedded struct, e.g. street of address_street, which is the gorm column
+// and the key of nested encodings
+func embeddedColumn(f *Field) string {
+	if g := f.embed; g != nil {
+		return strings.TrimPrefix(f.Field, g.Prefix)
+	}
+	return f.Field
+}
+
+// jsonName json key of the embedded struct field, of the prefix without trailing underscore, e.g. address of address_
+func (g *embedGroup) jsonName(options *Options) string {
+	return jsonName(options, strings.TrimSuffix(g.Prefix, "_"))
+}
+
+// match returns true if fields are the same columns of the group, of the same nullability and go type,
+// e.g. overridden type or enum
+func (g *embedGroup) match(options *Options, fields []*Field) bool {
+	if len(fields) != len(g.fields) {
+		return false
+	}
+	for i, f := range fields {
+		it := g.fields[i]
+		if strings.TrimPrefix(f.Field, g.Prefix) != it.Field || f.Type != it.Type || f.Nullable != it.Nullable {
+			return false
+		}
+		if goFieldType(options, f, jen.Null()).GoString() != goFieldType(options, it, jen.Null()).GoString() {
+			return false
+		}
+	}
+	return true
+}
+
+func goEmbedStruct(options *Options, g *embedGroup) jen.Code {
+	fields := make([]jen.Code, 0, len(g.fields))
+	for _, f := range g.fields {
+		fields = append(fields, goField(options, g.table, f))
+	}
+	return jen.Commentf("%s embedded columns with prefix %s", g.Name, g.Prefix).Line().
+		Type().Id(g.Name).Struct(fields...)
+}
+
+// goEmbedField embedded struct field of parent struct
+func goEmbedField(options *Options, g *embedGroup) jen.Code {
+	c := jen.Id(g.Name).Id(g.Name)
+	tag := make(map[string]string)
+	if options.GenGormTag {
+		tag["gorm"] = "embedded;embeddedPrefix:" + g.Prefix
+	}
+	if options.GenJsonTag {
+		tag["json"] = g.jsonName(options)
+	}
+	if len(tag) > 0 {
+		c.Tag(tag)
+	}
+	return c
+}
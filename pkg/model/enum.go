@@ -0,0 +1,263 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// enum go type generated for enum columns
+type enum struct {
+	Name   string
+	Values []string
+	// Ints values of int enum, Values are names of the values
+	Ints []int
+	// GoType backing type of int enum
+	GoType string
+	// set values of mysql set column, comma separated members of Values
+	set bool
+	// fields columns using this enum, as `table.column`
+	fields []string
+	tables []*Table
+}
+
+// shared returns true if enum is used by more than one table
+func (e *enum) shared() bool {
+	return len(e.tables) > 1
+}
+
+func (e *enum) use(table *Table, field *Field) {
+	e.fields = append(e.fields, fmt.Sprint(table.Name, ".", field.Field))
+	for _, t := range e.tables {
+		if t == table {
+			return
+		}
+	}
+	e.tables = append(e.tables, table)
+}
+
+// parseEnumValues parse values of mysql enum or set column type, e.g. enum('a','b'), set('a','b')
+func parseEnumValues(columnType string) []string {
+	i := strings.Index(columnType, "(")
+	if i < 0 || columnType[:i] != "enum" && columnType[:i] != "set" || !strings.HasSuffix(columnType, ")") {
+		return nil
+	}
+
+	s := columnType[i+1 : len(columnType)-1]
+	values := make([]string, 0, 4)
+	var (
+		value  strings.Builder
+		quoted bool
+	)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\'' && quoted && i+1 < len(s) && s[i+1] == '\'':
+			value.WriteByte(ch)
+			i++
+		case ch == '\'':
+			quoted = !quoted
+			if !quoted {
+				values = append(values, value.String())
+				value.Reset()
+			}
+		case quoted:
+			value.WriteByte(ch)
+		}
+	}
+	return values
+}
+
+// resolveEnums assign go enum type to enum fields of tables, identical value sets
+// share one enum type when options.ShareEnum is set, integer columns of options.IntEnumColumns
+// are int enums
+func resolveEnums(options *Options, tables []*Table) []*enum {
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			f.goEnum = nil
+		}
+	}
+
+	enums := make([]*enum, 0, 8)
+	set := make(map[string]*enum)
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			if values, ok := options.IntEnumColumns[table.Name][f.Field]; ok && len(values) > 0 {
+				e := intEnum(table, f, values)
+				e.use(table, f)
+				f.goEnum = e
+				enums = append(enums, e)
+				continue
+			}
+
+			if !options.GenEnum || len(f.EnumValues) == 0 {
+				continue
+			}
+
+			isSet := strings.HasPrefix(f.Type, "set(")
+			key := fmt.Sprint(isSet, "\x00", strings.Join(f.EnumValues, "\x00"))
+			e, ok := set[key]
+			if !ok || !options.ShareEnum {
+				e = &enum{Values: f.EnumValues, set: isSet}
+				set[key] = e
+				enums = append(enums, e)
+			}
+			e.use(table, f)
+			f.goEnum = e
+
+			// first column of the enum decides the name, unless configured
+			if name, ok := options.EnumNames[fmt.Sprint(table.Name, ".", f.Field)]; ok && name != "" {
+				e.Name = name
+			} else if e.Name == "" {
+				e.Name = goStructName(table) + TitleCase(f.Field)
+			}
+		}
+	}
+
+	return enums
+}
+
+// intEnum enum of integer column, values are sorted by value
+func intEnum(table *Table, field *Field, values map[string]int) *enum {
+	e := &enum{
+		Name:   goStructName(table) + TitleCase(field.Field),
+		GoType: field.GoType,
+	}
+	if !strings.Contains(e.GoType, "int") {
+		e.GoType = "int"
+	}
+
+	for name := range values {
+		e.Values = append(e.Values, name)
+	}
+	sort.Slice(e.Values, func(i, j int) bool {
+		a, b := values[e.Values[i]], values[e.Values[j]]
+		return a < b || a == b && e.Values[i] < e.Values[j]
+	})
+	for _, name := range e.Values {
+		e.Ints = append(e.Ints, values[name])
+	}
+	return e
+}
+
+// constNames constant names of enum values
+func (e *enum) constNames() []string {
+	names := make([]string, 0, len(e.Values))
+	set := make(map[string]bool)
+	for i, v := range e.Values {
+		name := e.Name + TitleCase(v)
+		if name == e.Name || set[name] {
+			name = fmt.Sprint(e.Name, "Value", i)
+		}
+		set[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func goEnum(options *Options, e *enum) *jen.Statement {
+	if e.Ints != nil {
+		return goIntEnum(options, receiverName(options, e.Name, "e"), e)
+	}
+
+	consts := make([]jen.Code, 0, len(e.Values))
+	for i, name := range e.constNames() {
+		consts = append(consts, jen.Id(name).Id(e.Name).Op("=").Lit(e.Values[i]))
+	}
+
+	return jen.Commentf("%s enum of %s", e.Name, strings.Join(e.fields, ", ")).Line().
+		Type().Id(e.Name).Add(enumBaseType(options.EnumBaseType, jen.String())).Line().Line().
+		Const().Defs(consts...).Line().Line().
+		Add(goEnumValid(receiverName(options, e.Name, "e"), e)).
+		Add(goEnumBase(options.EnumBaseType, receiverName(options, e.Name, "e"), e))
+}
+
+// goEnumValid `Valid()` returns true if value is one of the enum values, or comma separated values of set
+func goEnumValid(recv string, e *enum) *jen.Statement {
+	names := make([]jen.Code, 0, len(e.Values))
+	for _, name := range e.constNames() {
+		names = append(names, jen.Id(name))
+	}
+
+	if !e.set {
+		return jen.Comment("Valid returns true if value is one of the enum values").Line().
+			Func().Params(jen.Id(recv).Id(e.Name)).Id("Valid").Params().Bool().Block(
+			jen.Switch(jen.Id(recv)).Block(jen.Case(names...).Block(jen.Return(jen.True()))),
+			jen.Return(jen.False()),
+		)
+	}
+
+	v := "v"
+	if recv == v {
+		v = "s"
+	}
+	return jen.Comment("Valid returns true if value is comma separated enum values, empty value is the empty set").Line().
+		Func().Params(jen.Id(recv).Id(e.Name)).Id("Valid").Params().Bool().Block(
+		jen.If(jen.Id(recv).Op("==").Lit("")).Block(jen.Return(jen.True())),
+		jen.For(jen.List(jen.Id("_"), jen.Id(v)).Op(":=").Range().Qual("strings", "Split").Call(jen.String().Call(jen.Id(recv)), jen.Lit(","))).Block(
+			jen.Switch(jen.Id(e.Name).Call(jen.Id(v))).Block(
+				jen.Case(names...),
+				jen.Default().Block(jen.Return(jen.False())),
+			),
+		),
+		jen.Return(jen.True()),
+	)
+}
+
+// enumBaseType qualified base type of enums, e.g. github.com/acme/enums.StringEnum, def if not set
+func enumBaseType(baseType string, def *jen.Statement) *jen.Statement {
+	if baseType == "" {
+		return def
+	}
+	return jen.Qual(qualifiedType(baseType))
+}
+
+// goEnumBase `Base()` converts enum to its base type, enum is a new defined type that has none of
+// the base type methods, they are called by Base, e.g. OrderStateNew.Base().Label(). Empty without base type
+func goEnumBase(baseType, recv string, e *enum) *jen.Statement {
+	if baseType == "" {
+		return jen.Null()
+	}
+	return jen.Line().Line().Comment("Base returns the value as base type, calling its methods").Line().
+		Func().Params(jen.Id(recv).Id(e.Name)).Id("Base").Params().Add(enumBaseType(baseType, nil)).Block(
+		jen.Return(enumBaseType(baseType, nil).Call(jen.Id(recv))),
+	)
+}
+
+// goIntEnum int backed enum with sql.Scanner and driver.Valuer
+func goIntEnum(options *Options, recv string, e *enum) *jen.Statement {
+	consts := make([]jen.Code, 0, len(e.Values))
+	for i, name := range e.constNames() {
+		consts = append(consts, jen.Id(name).Id(e.Name).Op("=").Lit(e.Ints[i]))
+	}
+
+	// local names of Scan must not shadow the receiver
+	src, v := "value", "v"
+	if recv == src {
+		src = "src"
+	}
+	if recv == v {
+		v = "n"
+	}
+
+	return jen.Commentf("%s enum of %s", e.Name, strings.Join(e.fields, ", ")).Line().
+		Type().Id(e.Name).Add(enumBaseType(options.IntEnumBaseType, jen.Id(e.GoType))).Line().Line().
+		Const().Defs(consts...).Line().Line().
+		Comment("Scan implements sql.Scanner").Line().
+		Func().Params(jen.Id(recv).Op("*").Id(e.Name)).Id("Scan").Params(jen.Id(src).Interface()).Error().Block(
+		jen.Var().Id(v).Qual("database/sql", "NullInt64"),
+		jen.If(jen.Err().Op(":=").Id(v).Dot("Scan").Call(jen.Id(src)), jen.Err().Op("!=").Nil()).Block(
+			jen.Return(jen.Err()),
+		),
+		jen.Op("*").Id(recv).Op("=").Id(e.Name).Call(jen.Id(v).Dot("Int64")),
+		jen.Return(jen.Nil()),
+	).Line().Line().
+		Comment("Value implements driver.Valuer").Line().
+		Func().Params(jen.Id(recv).Id(e.Name)).Id("Value").Params().Params(jen.Qual("database/sql/driver", "Value"), jen.Error()).Block(
+		jen.Return(jen.Int64().Call(jen.Id(recv)), jen.Nil()),
+	).Line().Line().
+		Add(goEnumValid(recv, e)).
+		Add(goEnumBase(options.IntEnumBaseType, recv, e))
+}
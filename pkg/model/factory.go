@@ -0,0 +1,36 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goFactory `New<Name>Fixture()` returns model populated with fake values for tests,
+// zero values, nullable columns and auto increment columns are left unset
+func goFactory(options *Options, name string, table *Table) *jen.Statement {
+	base := baseField(options, table)
+	values := make(jen.Dict)
+	for _, f := range table.Fields {
+		if f == base || f.embed != nil || f.Nullable || f.AutoIncrement {
+			continue
+		}
+		if _, ok := typeOverride(options, f); ok && f.goEnum == nil {
+			continue
+		}
+
+		var v jen.Code
+		switch {
+		case f.goEnum != nil:
+			v = jen.Id(f.goEnum.constNames()[0])
+		case f.GoType == "string":
+			v = jen.Lit("test")
+		case f.GoType == "time.Time" && options.TimeType == "":
+			v = jen.Qual("time", "Now").Call()
+		default:
+			continue
+		}
+		values[jen.Id(goFieldName(f))] = v
+	}
+
+	return jen.Commentf("New%sFixture returns %s populated with fake values for tests", name, name).Line().
+		Func().Id("New" + name + "Fixture").Params().Op("*").Id(name).Block(
+		jen.Return(jen.Op("&").Id(name).Values(values)),
+	)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// goFieldName go field name of column, truncated by options.MaxFieldNameLength if resolved
+func goFieldName(f *Field) string {
+	if f.goName != "" {
+		return f.goName
+	}
+	return TitleCase(f.Field)
+}
+
+// foldName lower case of upper case identifier if options.FoldUpperCaseNames or oracle, e.g. USER_ID -> user_id
+func foldName(options *Options, name string) string {
+	if (options.FoldUpperCaseNames || options.DbType == DbTypeOracle) && name == strings.ToUpper(name) {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// resolveFieldNames fold go names of upper case tables and columns, see foldName, and truncate
+// go field names longer than options.MaxFieldNameLength, a number is appended if the truncated
+// name collides with another field of the table, the first letter is always kept so the name may
+// exceed tiny limits then
+func resolveFieldNames(options *Options, tables []*Table) {
+	max := options.MaxFieldNameLength
+	for _, table := range tables {
+		table.goName = ""
+		if name := strings.TrimPrefix(table.Name, table.Prefix); foldName(options, name) != name {
+			table.goName = TitleCase(foldName(options, name))
+		}
+		for _, f := range table.Fields {
+			f.goName = ""
+			if name := foldName(options, f.Field); name != f.Field {
+				f.goName = TitleCase(name)
+			}
+		}
+		if max <= 0 {
+			continue
+		}
+
+		used := make(map[string]bool, len(table.Fields))
+		for _, f := range table.Fields {
+			if name := goFieldName(f); len(name) <= max {
+				used[name] = true
+			}
+		}
+
+		for _, f := range table.Fields {
+			name := goFieldName(f)
+			if len(name) <= max {
+				continue
+			}
+
+			truncated := name[:max]
+			for i := 2; used[truncated]; i++ {
+				suffix := strconv.Itoa(i)
+				// keep the first letter, so tiny limits still give identifiers, e.g. A2 of limit 1
+				cut := max - len(suffix)
+				if cut < 1 {
+					cut = 1
+				}
+				truncated = name[:cut] + suffix
+			}
+			used[truncated] = true
+			f.goName = truncated
+		}
+	}
+}
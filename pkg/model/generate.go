@@ -1,13 +1,20 @@
 package model
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dave/jennifer/jen"
@@ -18,10 +25,47 @@ import (
 const (
 	DbTypeMySQL      = "mysql"
 	DbTypePostgreSQL = "postgresql"
+	DbTypeSpanner    = "spanner"
+	DbTypeRedshift   = "redshift"
+	DbTypeSQLite     = "sqlite"
+	DbTypeOracle     = "oracle"
+	DbTypeSQLServer  = "sqlserver"
 )
 
+const (
+	FieldOrderDb      = "db"
+	FieldOrderAlpha   = "alpha"
+	FieldOrderPkFirst = "pk-first"
+)
+
+const (
+	JsonTagCamel    = "camel"
+	JsonTagSnake    = "snake"
+	JsonTagOriginal = "original"
+	// EnvTagUpperSnake default casing of env tags, e.g. MAX_CONNS
+	EnvTagUpperSnake = "upper-snake"
+)
+
+const (
+	NullablePointer = "pointer"
+	NullableSql     = "sql"
+	NullableCustom  = "custom"
+	// NullableGuregu custom nullable types of gopkg.in/guregu/null.v4 unless NullablePackage is set
+	NullableGuregu = "guregu"
+)
+
+// gureguNullPackage package of NullableGuregu
+const gureguNullPackage = "gopkg.in/guregu/null.v4"
+
+// defaultDeprecatedPrefixes column comment prefixes mark column deprecated
+var defaultDeprecatedPrefixes = []string{"DEPRECATED", "@deprecated"}
+
+// ReceiverShort receiver name is lowercase first letter of type name, e.g. u for User
+const ReceiverShort = "short"
+
 var (
 	ErrTypeNotSupported = errors.New("type not found")
+	ErrDuplicateFile    = errors.New("generated files have the same name")
 
 	l = log.New(os.Stdout, "[database-struct] ", log.LstdFlags)
 )
@@ -29,6 +73,7 @@ var (
 type Options struct {
 	DbType           string
 	Dsn              string
+	DatabaseURL      string
 	GenGormTag       bool
 	GormV1           bool
 	GenJsonTag       bool
@@ -39,6 +84,320 @@ type Options struct {
 	Filters          []*Filter
 	Exclude          []string
 	Verbose          bool
+
+	// GenPrimaryKeyMethod generate `PrimaryKey() any` method, so models can satisfy a generic constraint
+	GenPrimaryKeyMethod bool
+	// HtmlColumnIndex add a searchable index of all columns to the html report
+	HtmlColumnIndex bool
+	// GenEnum generate go enum type and constants for enum columns
+	GenEnum bool
+	// ShareEnum reuse one enum type for columns with identical enum values
+	ShareEnum bool
+	// EnumNames custom enum type name, key is `table.column`
+	EnumNames map[string]string
+	// StrictTypes return error for type mappings that may lose precision, e.g. decimal -> float64, and for
+	// unknown db types not in TypeOverrides, which fall back to interface{} otherwise
+	StrictTypes bool
+	// StrictTypesAllow columns allowed to lose precision under StrictTypes, as `table.column`
+	StrictTypesAllow []string
+	// ChangeLogFile write markdown changelog of schema changes since previous snapshot
+	ChangeLogFile string
+	// SnapshotFile schema snapshot compared by ChangeLogFile, default schema.json beside the changelog
+	SnapshotFile string
+	// GenConformTag generate leebenson/conform tags, `trim` for string columns by default
+	GenConformTag bool
+	// ConformRules conform tag per column, key is `table.column` or `column`, empty value skip the tag
+	ConformRules map[string]string
+	// NullableStrategy go type of nullable columns: pointer(default), sql, custom, guregu
+	NullableStrategy string
+	// NullablePackage package of custom nullable types, e.g. gopkg.in/guregu/null.v4
+	NullablePackage string
+	// NullableTypes custom nullable type name of go type, e.g. string -> String
+	NullableTypes map[string]string
+	// GenFieldMeta generate `[]FieldMeta` column metadata var for each model
+	GenFieldMeta bool
+	// BaseStruct name of shared base struct embedded by tables with matching primary key
+	BaseStruct string
+	// BaseColumn primary key column of BaseStruct, default id
+	BaseColumn string
+	// BaseGoType primary key go type of BaseStruct, default int64
+	BaseGoType string
+	// SharedFile file of code shared by models in multiple files mode, default shared.go
+	SharedFile string
+	// EnumFile route all generated enums to this file instead of the model files, e.g. enums_gen.go
+	EnumFile string
+	// ConstantsFile route condition and type constants of columns to this file instead of the model files,
+	// e.g. constants_gen.go, see GenConditions and GenTypeConstants
+	ConstantsFile string
+	// IAMAuth use AWS RDS IAM auth token as password, requires build tag `aws`
+	IAMAuth *IAMAuth
+	// FieldOrder order of generated struct fields: db(default), alpha, pk-first
+	FieldOrder string
+	// SequenceComment note the owned sequence of column in field comment (postgresql)
+	SequenceComment bool
+	// GenSequenceConstants introspect standalone sequences not owned by columns into Sequences, and generate
+	// their name and nextval query constants to shared code or ConstantsFile (postgresql)
+	GenSequenceConstants bool
+	// Sequences standalone sequences of GenSequenceConstants, filled by DbStruct
+	Sequences []string
+	// SchemaFile committed schema snapshot to generate from, see SchemaTables
+	SchemaFile string
+	// UpdateSnapshot introspect db and rewrite SchemaFile
+	UpdateSnapshot bool
+	// GenTableNameFunc generate `TableName()` calling package level `TableNameFunc` hook for all tables
+	GenTableNameFunc bool
+	// CreateOnlyColumns columns only set on create by gorm, as `table.column` or `column`
+	CreateOnlyColumns []string
+	// MermaidFile generate Mermaid ER diagram file
+	MermaidFile string
+	// NullableBool type of nullable bool columns instead of nullable strategy, e.g. sql.NullBool
+	NullableBool string
+	// ShardOutputByLetter place model files in sub dir by first letter of struct name in multiple files mode,
+	// e.g. u/user.go, names not starting with a letter go to 0. Each sub dir is a package named ModelPackageName
+	// with its own copy of shared code used by its models, options referring models of other sub dirs are rejected
+	ShardOutputByLetter bool
+	// EmbedPrefixGroups fold columns with prefix into shared embedded struct, e.g. address_, gorm column and json key
+	// of the embedded struct fields are trimmed, other tags are of the full column name, e.g. db:"address_street"
+	EmbedPrefixGroups []string
+	// GenFactory generate `New<Model>Fixture()` returning model with fake values for tests
+	GenFactory bool
+	// GenSchemaMethod generate `Schema() string` method returning schema of table
+	GenSchemaMethod bool
+	// SchemaName schema returned by `Schema()` instead of introspected schema
+	SchemaName string
+	// IntEnumColumns generate int enum for integer columns, table -> column -> {name: value}
+	IntEnumColumns map[string]map[string]map[string]int
+	// LicenseHeader raw license text prepended to generated go files as line comments
+	LicenseHeader string
+
+	// GenGormComment add column comment to gorm tag
+	GenGormComment bool
+	// GenGormCheck add check constraint of column to gorm tag
+	GenGormCheck bool
+
+	// GenHookStubs generate empty gorm hook methods to editable _hook.go files
+	GenHookStubs bool
+
+	// MergeTags keep hand-written tag keys of fields in existing files of ModelDir on regeneration
+	MergeTags bool
+
+	// ReceiverName receiver variable name of generated methods, ReceiverShort or custom name
+	ReceiverName string
+
+	// GenInit generate init() registering models to package level Models, or calling InitRegisterFunc
+	GenInit bool
+	// InitRegisterFunc qualified register func called with each model, e.g. github.com/acme/app/registry.Register
+	InitRegisterFunc string
+
+	// PIIColumns columns tagged as pii, as `table.column` or `column`
+	PIIColumns []string
+	// PIITag tag key of pii columns, default pii
+	PIITag string
+	// GenPIIFieldsMethod generate `PIIFields() []string` returns pii columns
+	GenPIIFieldsMethod bool
+
+	// GenBoilTags generate boil, toml and yaml tags the way sqlboiler does, json tag follows GenJsonTag
+	GenBoilTags bool
+
+	// DeprecatedPrefixes column comment prefixes generate `// Deprecated:` doc of field, case insensitive,
+	// default DEPRECATED and @deprecated
+	DeprecatedPrefixes []string
+
+	// JsonInt64AsString add `,string` to json tag of int64 and uint64 columns, for js clients
+	JsonInt64AsString bool
+
+	// Generators names of registered generators run by Generate in order, default html, go, mermaid,
+	// changelog and mapping
+	Generators []string
+
+	// MappingFile write struct field, json key, db column and type mapping of models, csv if ext is .csv, markdown otherwise
+	MappingFile string
+
+	// GenRelations generate belongs-to association fields of foreign keys, nullable column is pointer association,
+	// and many2many association fields of pure pivot tables
+	GenRelations bool
+
+	// MaxFieldNameLength truncate go field names longer than it, a number is appended on collision, 0 is unlimited
+	MaxFieldNameLength int
+
+	// GenSortHelpers generate sort.Interface slice types by primary key and indexed columns, e.g. UsersById
+	GenSortHelpers bool
+
+	// TimeType qualified type of time columns instead of time.Time, e.g. github.com/golang-module/carbon.Carbon,
+	// nullable time columns still follow NullableStrategy
+	TimeType string
+
+	// GenScopes generate gorm scopes of soft delete, boolean columns and active value of enum columns
+	GenScopes bool
+
+	// GenComputed add read only gorm permission `->;<-:false` and `computed: <expression>` comment to generated columns
+	GenComputed bool
+
+	// GenConditions generate where condition constants of columns, e.g. UserWhereId = "id = ?"
+	GenConditions bool
+
+	// GenMutex add unexported `mu sync.RWMutex` field and locked accessor methods to models,
+	// methods of models have pointer receivers then
+	GenMutex bool
+
+	// SkipPivotTables skip models of pure pivot tables, which are many2many associations of GenRelations
+	SkipPivotTables bool
+
+	// GenRepositoryInterface generate `<Name>Repository` interface of crud methods for each model to RepositoryDir,
+	// ModelImportPath is required to refer models
+	GenRepositoryInterface bool
+	// GenRepository generate repository interfaces and their implementations of RepositoryDriver with Get, List,
+	// Create, Update and Delete to RepositoryDir, constructed by `New<Name>Repository`, ModelImportPath is required
+	GenRepository bool
+	// RepositoryDir dir of repository interfaces relative to ModelDir, default repository, package name is its base name
+	RepositoryDir string
+	// ModelImportPath import path of model package, e.g. github.com/acme/app/model
+	ModelImportPath string
+
+	// TypeOverrides go type of `table.column`, db type or go type, overrides built-in mapping, db type matches with
+	// or without length and precision, e.g. decimal=github.com/shopspring/decimal.Decimal, unknown db types must be mapped
+	TypeOverrides map[string]string
+
+	// CollationComment note the collation of column in field comment (mysql)
+	CollationComment bool
+	// BinaryCollationBytes map string columns of binary collation, e.g. utf8mb4_bin, to []byte
+	BinaryCollationBytes bool
+
+	// GenJSONMarshaler generate MarshalJSON method omitting nil pointer fields for models with nullable pointer fields
+	GenJSONMarshaler bool
+
+	// JsonTagStyle naming of json keys: camel(default), snake, original column name
+	JsonTagStyle string
+	// JsonOmitEmpty append omitempty to json tags, except nullable pointer fields unless JsonOmitEmptyNullable is set
+	JsonOmitEmpty         bool
+	JsonOmitEmptyNullable bool
+
+	// Concurrency workers generating go structs of tables, default GOMAXPROCS
+	Concurrency int
+
+	// EnumBaseType and IntEnumBaseType qualified base type of string and int enums, e.g. github.com/acme/enums.StringEnum,
+	// underlying type must be string and integer, default string and go type of column. Only the underlying type is
+	// shared, methods of the base type are reachable by the generated `Base()` conversion, e.g. OrderStateNew.Base().Label()
+	EnumBaseType    string
+	IntEnumBaseType string
+
+	// HistorySuffix suffix of history tables paired with their tables, e.g. _history of user_history,
+	// history model notes the pair and has `Snapshot()` returning the model of its table
+	HistorySuffix string
+
+	// SecretColumns secret columns are not serialized by json, toml and yaml, as `table.column` or `column`,
+	// may be patterns, e.g. *password*, see path.Match
+	SecretColumns []string
+	// SecretNoRead add gorm `->:false` to secret columns, so they are not read by default
+	SecretNoRead bool
+
+	// MigrationDir generate golang-migrate init migration files creating and dropping tables to dir, e.g. migrations,
+	// an existing init migration is rewritten in place
+	MigrationDir string
+
+	// GenTypeConstants generate db type constants of columns for raw sql casts, e.g. UserIdType = "bigint"
+	GenTypeConstants bool
+
+	// WarnColumnReorder warn about columns whose ordinal changed since SnapshotFile, order sensitive outputs change then
+	WarnColumnReorder bool
+
+	// BoolStrategy bool mapping of tinyint columns: tinyint1 for tinyint(1) and oracle NUMBER(1), all-tinyint,
+	// by-name for tinyint columns matching BoolNamePatterns, default is_*, has_* and can_*, none or not set keeps integers.
+	// IntEnumColumns and TypeOverrides take precedence, tinyint(1) columns have bool scopes if not set
+	BoolStrategy     string
+	BoolNamePatterns []string
+
+	// UnsignedBigintPK go type of `bigint unsigned` primary keys beyond js safe integers, e.g. snowflake ids, and of
+	// `bigint unsigned` foreign keys referencing them: string, json-string for uint64 with json `,string`, default uint64
+	UnsignedBigintPK string
+
+	// FilePerPrefixGroup generate one file per prefix of Filters, e.g. auth.go of tables auth_*,
+	// tables without prefix go to model.go
+	FilePerPrefixGroup bool
+
+	// DbManagedDefaults gorm `default:(-)` of columns with expression default, e.g. CURRENT_TIMESTAMP,
+	// gorm doesn't send go value and reads back the db generated value then, gorm v2 only
+	DbManagedDefaults bool
+
+	// GenRepositoryRegistry generate `Repositories`, factories of repository implementations keyed by table name,
+	// requires GenRepository
+	GenRepositoryRegistry bool
+
+	// LogSQL log introspection queries with their duration
+	LogSQL bool
+
+	// GenEnvTag generate env or config tags of columns for config tables, key EnvTagKey, default env,
+	// e.g. mapstructure, casing EnvTagCase: upper-snake(default), snake, camel, original column name
+	GenEnvTag  bool
+	EnvTagKey  string
+	EnvTagCase string
+
+	// GenIdentifiable generate `GetID()` and `SetID(id)` of models with single column primary key,
+	// so they satisfy generated `Identifiable` interface, composite primary key tables are skipped
+	GenIdentifiable bool
+
+	// GenDocMap generate `<Name>FieldDocs` map of go field names to column comments, e.g. for api docs
+	GenDocMap bool
+
+	// FoldUpperCaseNames derive go and json names of upper case tables and columns from their lower case,
+	// e.g. USER_ID -> UserId, db names are kept in tags, always set for oracle
+	FoldUpperCaseNames bool
+
+	// GenTests generate model_test.go checking tables and columns of models resolved by gorm schema parser,
+	// naming strategy of the tests is singular if TestSingularTable is set, gorm v2 only
+	GenTests          bool
+	TestSingularTable bool
+	// SqlFile sql dump of CREATE TABLE statements to generate from without db access, column types are of
+	// DbType, mysql or postgresql. Unqualified names are of `SET search_path` or `USE`, tables of the same name
+	// in different schemas are reported unless all but one are in Exclude as `schema.table`
+	SqlFile string
+
+	// GenHasMany generate has-many association fields of tables referred by foreign keys, has-one if the column
+	// is unique, use with GenRelations
+	GenHasMany bool
+	// InferRelations infer foreign keys of `<table>_id` columns referring single primary key of `<table>` or `<table>s`,
+	// for associations and the html report
+	InferRelations bool
+
+	// CodeTemplateDir dir of pongo2 templates rendered to model files instead of built-in generation,
+	// table.go.tpl is rendered for each table, other *.go.tpl once, e.g. shared.go.tpl -> shared.go
+	CodeTemplateDir string
+
+	// RepositoryDriver driver of repository implementations of GenRepository, gorm(default) or sql,
+	// sql ones use database/sql with bind vars and quoting of DbType
+	RepositoryDriver string
+
+	// Tables settings of tables keyed by table name, usually of config file, see LoadConfig
+	Tables map[string]*TableConfig
+
+	// DB existing connection to introspect instead of connecting by Dsn, it's not closed
+	DB *sql.DB
+	// IntrospectConcurrency workers introspecting tables, default GOMAXPROCS
+	IntrospectConcurrency int
+
+	// Incremental rewrite go files only if their code changed, the timestamp header is ignored, generated go files
+	// of ModelDir no longer generated are removed, e.g. model of a dropped table
+	Incremental bool
+	// Check write nothing, returns ErrOutdated listing go files whose code changed or which are no longer generated, e.g. for ci
+	Check bool
+	// PrintDiff print changes of tables since SnapshotFile, the snapshot is updated unless Check
+	PrintDiff bool
+
+	// MarkdownFile write markdown data dictionary of tables and columns
+	MarkdownFile string
+	// JsonFile write json dump of tables, same as DumpTables
+	JsonFile string
+	// OpenApiFile write openapi component schemas of models, json if ext is .json, yaml otherwise
+	OpenApiFile string
+
+	// Tags names of registered tag generators adding struct tags to fields in order, built-in db (sqlx),
+	// bun, xorm and validate (go-playground validator), keys set by former generators are kept.
+	// Built-in gorm, json, env, conform, boil and pii generators are enabled by their options, e.g. GenGormTag,
+	// and come first unless listed here to reorder them; disable the option and add a generator of the same key
+	// to replace one. ent is not a tag generator, its models are generated from schema code instead of struct tags
+	Tags []string
+	// TagGenerators tag generators applied after Tags, e.g. TagFunc of custom tags
+	TagGenerators []TagGenerator
 }
 
 type Filter struct {
@@ -59,168 +418,1032 @@ func NewFilter(prefix string, pattern string) *Filter {
 }
 
 type strutter interface {
-	dbStruct(*Options) ([]*Table, error)
+	dbStruct(context.Context, *Options) ([]*Table, error)
 }
 
 func Generate(options *Options, tables []*Table) error {
+	return GenerateContext(context.Background(), options, tables)
+}
+
+// GenerateContext Generate, stops early with ctx.Err() when ctx is cancelled
+func GenerateContext(ctx context.Context, options *Options, tables []*Table) error {
 	if options.Verbose {
 		l.Println("generate table go struct code")
 	}
 
-	for _, table := range tables {
-		goStruct(options, table)
-	}
-
-	if options.HtmlFile != "" {
-		tpl := pongo2.Must(pongo2.FromString(pkgerReadString("/template/struct.html")))
-		// tpl := pongo2.Must(pongo2.FromFile("template/struct.html"))
-		data := pongo2.Context{
-			"tables":     tables,
-			"tableCount": len(tables),
-			"date":       time.Now().Format("2006-01-02 15:04:05"),
-			"style": []string{
-				pkgerReadString("/assets/style.css"),
-				pkgerReadString("/assets/prism/1.20.0/prism.css"),
-			},
-			"script": []string{
-				pkgerReadString("/assets/prism/1.20.0/prism.js"),
-			},
-		}
-		file, err := os.OpenFile(options.HtmlFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
-		if err != nil {
+	if err := checkTagGenerators(options); err != nil {
+		return err
+	}
+
+	enums, embeds, err := prepare(ctx, options, tables)
+	if err != nil {
+		return err
+	}
+
+	if options.WarnColumnReorder {
+		if err := warnColumnReorders(options, tables); err != nil {
+			return err
+		}
+	}
+
+	if options.PrintDiff {
+		if err := printDiff(options, tables, os.Stdout); err != nil {
 			return err
 		}
+	}
 
-		err = tpl.ExecuteWriter(data, file)
+	// check compares go files only, other outputs are not written
+	if options.Check {
+		return writeModels(ctx, options, tables, enums, embeds)
+	}
+
+	names := options.Generators
+	if len(names) == 0 {
+		names = defaultGenerators
+	}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		g, ok := generators[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrGeneratorNotFound, name)
+		}
+		if p, ok := g.(preparedGenerator); ok {
+			err = p.generatePrepared(ctx, options, tables, enums, embeds)
+		} else {
+			err = g.Generate(options, tables)
+		}
 		if err != nil {
 			return err
 		}
-		_ = file.Close()
 	}
 
-	if options.ModelDir != "" {
-		pkgName := options.ModelPackageName
-		if pkgName == "" {
-			pkgName = "model"
+	// changelog updates the snapshot itself
+	if options.PrintDiff && options.ChangeLogFile == "" {
+		if err := DumpTables(snapshotFile(options), tables); err != nil {
+			return err
+		}
+	}
+
+	if options.Verbose {
+		l.Println("Done")
+	}
+
+	return nil
+}
+
+// writeHtml write html document of tables to options.HtmlFile
+func writeHtml(options *Options, tables []*Table) error {
+	if options.HtmlFile == "" {
+		return nil
+	}
+
+	tpl := pongo2.Must(pongo2.FromString(pkgerReadString("/template/struct.html")))
+	// tpl := pongo2.Must(pongo2.FromFile("template/struct.html"))
+	data := pongo2.Context{
+		"tables":     tables,
+		"tableCount": len(tables),
+		"date":       time.Now().Format("2006-01-02 15:04:05"),
+		"style": []string{
+			pkgerReadString("/assets/style.css"),
+			pkgerReadString("/assets/prism/1.20.0/prism.css"),
+		},
+		"script": []string{
+			pkgerReadString("/assets/prism/1.20.0/prism.js"),
+		},
+	}
+	edges := relationEdges(tables)
+	data["relations"] = func(name string) []*relationEdge {
+		return edges[name]
+	}
+	if options.HtmlColumnIndex {
+		data["columnIndex"] = columnIndex(tables)
+		data["script"] = append(data["script"].([]string), pkgerReadString("/assets/search.js"))
+	}
+	file, err := os.OpenFile(options.HtmlFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tpl.ExecuteWriter(data, file)
+}
+
+// writeModels save go files of GenerateFiles, or of templates of options.CodeTemplateDir, of prepared tables
+// to options.ModelDir, existing hook stub files are kept, see saveGenerated for options.Incremental and options.Check
+func writeModels(ctx context.Context, options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) error {
+	if options.ModelDir == "" {
+		return nil
+	}
+
+	var (
+		files map[string][]byte
+		err   error
+	)
+	if options.CodeTemplateDir != "" {
+		files, err = templateFiles(ctx, options, tables)
+	} else {
+		files, err = renderFiles(options, tables, enums, embeds)
+	}
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return saveGenerated(options, names, files)
+}
+
+// renderFiles go sources of GenerateFiles of prepared tables, existing hook stub files are skipped
+func renderFiles(options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) (map[string][]byte, error) {
+	files, stubs, err := generateFiles(options, tables, enums, embeds)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string][]byte, len(files))
+	for name, f := range files {
+		if _, err := os.Stat(filepath.Join(options.ModelDir, name)); err == nil && stubs[name] {
+			continue
+		}
+		var b bytes.Buffer
+		if err := f.Render(&b); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		sources[name] = b.Bytes()
+	}
+	return sources, nil
+}
+
+// GenerateFiles generate go files of tables without saving, keyed by file name relative to model dir,
+// callers may add more code to the files before saving. Hook stub files (suffix _hook.go) are
+// editable, Generate saves them only if missing. Returns ErrDuplicateFile if two outputs have the same name
+func GenerateFiles(options *Options, tables []*Table) (map[string]*jen.File, error) {
+	enums, embeds, err := prepare(context.Background(), options, tables)
+	if err != nil {
+		return nil, err
+	}
+	files, _, err := generateFiles(options, tables, enums, embeds)
+	return files, err
+}
+
+// GenerateToWriter write go source of all tables as one file to w, same as model.go of options.ModelSingleFile,
+// enums are included, hook stubs and repository interfaces in other files are not
+func GenerateToWriter(options *Options, tables []*Table, w io.Writer) error {
+	single := *options
+	single.ModelSingleFile = true
+	single.FilePerPrefixGroup = false
+	single.EnumFile = ""
+	single.ConstantsFile = ""
+	single.GenHookStubs = false
+	single.GenRepositoryInterface = false
+	single.GenRepository = false
+	single.GenRepositoryRegistry = false
+	single.GenTests = false
+
+	files, err := GenerateFiles(&single, tables)
+	if err != nil {
+		return err
+	}
+	return files["model.go"].Render(w)
+}
+
+// prepare resolve generated names, enums, embedded groups and relations of tables, then generate go structs
+func prepare(ctx context.Context, options *Options, tables []*Table) ([]*enum, []*embedGroup, error) {
+	resolveFieldNames(options, tables)
+	resolveTypeOverrides(options, tables)
+	resolveTableConfigs(options, tables)
+	resolveBools(options, tables)
+	resolveUnsignedPKs(options, tables)
+	if err := resolveUserTags(options, tables); err != nil {
+		return nil, nil, err
+	}
+	enums := resolveEnums(options, tables)
+	embeds := resolveEmbedGroups(options, tables)
+	resolveRelations(options, tables)
+	resolveHistories(options, tables)
+	if err := goStructs(ctx, options, tables); err != nil {
+		return nil, nil, err
+	}
+	return enums, embeds, nil
+}
+
+// goStructs generate go structs of tables by options.Concurrency workers, each table only
+// changes its own go struct
+func goStructs(ctx context.Context, options *Options, tables []*Table) error {
+	workers := options.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(tables) {
+		workers = len(tables)
+	}
+
+	queue := make(chan *Table)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for table := range queue {
+				goStruct(options, table)
+			}
+		}()
+	}
+
+	var err error
+	for _, table := range tables {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		queue <- table
+	}
+	close(queue)
+	wg.Wait()
+	return err
+}
+
+// generateFiles go files of tables and names of hook stub files in them
+func generateFiles(options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) (map[string]*jen.File, map[string]bool, error) {
+	if err := checkShardOptions(options); err != nil {
+		return nil, nil, err
+	}
+	if options.SkipPivotTables {
+		models := make([]*Table, 0, len(tables))
+		for _, table := range tables {
+			if !isPivot(table) {
+				models = append(models, table)
+			}
+		}
+		tables = models
+	}
+
+	pkgName := options.ModelPackageName
+	if pkgName == "" {
+		pkgName = "model"
+	}
+	sharedFile := options.SharedFile
+	if sharedFile == "" {
+		sharedFile = "shared.go"
+	}
+	headerComment := fmt.Sprintf("code generated by database-struct @%v", time.Now().Format("2006-01-02 15:04:05"))
+
+	files := make(map[string]*jen.File)
+	stubs := make(map[string]bool)
+	duplicates := make([]string, 0)
+	addFile := func(pkgName, name, header string, cs []jen.Code) {
+		name = filepath.ToSlash(name)
+		if _, ok := files[name]; ok {
+			duplicates = append(duplicates, name)
+			return
+		}
+		f := newFile(options, pkgName, header)
+		for _, c := range cs {
+			f.Add(c)
+			f.Line()
+		}
+		files[name] = f
+	}
+	addPkg := func(pkgName, name string, cs []jen.Code) {
+		addFile(pkgName, name, headerComment, cs)
+	}
+	add := func(name string, cs []jen.Code) {
+		addPkg(pkgName, name, cs)
+	}
+	addStub := func(name string, cs []jen.Code) {
+		stubs[filepath.ToSlash(name)] = true
+		addFile(pkgName, name, "hook stubs generated by database-struct, edit freely, existing file is not overwritten", cs)
+	}
+
+	if options.FilePerPrefixGroup {
+		groups, names, err := prefixGroups(tables)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range names {
+			cs := make([]jen.Code, 0, len(groups[name])*2)
+			for _, table := range groups[name] {
+				cs = append(cs, tableCode(options, enums, table)...)
+			}
+			add(name+".go", cs)
+
+			if options.GenHookStubs {
+				hooks := make([]jen.Code, 0, len(groups[name]))
+				for _, table := range groups[name] {
+					hooks = append(hooks, goHookStubs(options, table))
+				}
+				addStub(name+hookFileSuffix, hooks)
+			}
+		}
+
+		if shared := sharedCode(options, tables, enums, embeds); len(shared) > 0 {
+			add(sharedFile, shared)
+		}
+	} else if options.ModelSingleFile {
+		cs := sharedCode(options, tables, enums, embeds)
+		for _, table := range tables {
+			cs = append(cs, tableCode(options, enums, table)...)
+		}
+		add("model.go", cs)
+
+		if options.GenHookStubs {
+			hooks := make([]jen.Code, 0, len(tables))
+			for _, table := range tables {
+				hooks = append(hooks, goHookStubs(options, table))
+			}
+			addStub("model"+hookFileSuffix, hooks)
 		}
-		headerComment := fmt.Sprintf("code generated by database-struct @%v", time.Now().Format("2006-01-02 15:04:05"))
+	} else if options.ShardOutputByLetter {
+		shards, dirs := letterShards(tables)
+		for _, dir := range dirs {
+			for _, table := range shards[dir] {
+				baseName := filepath.Join(dir, strings.TrimPrefix(table.Name, table.Prefix))
+				add(baseName+".go", tableCode(options, enums, table))
+				if options.GenHookStubs {
+					addStub(baseName+hookFileSuffix, []jen.Code{goHookStubs(options, table)})
+				}
+			}
 
-		if _, err := os.Stat(options.ModelDir); os.IsNotExist(err) {
-			err = os.MkdirAll(options.ModelDir, 0700)
-			if err != nil {
-				return err
+			shardEnums, shardEmbeds := shardShared(shards[dir], enums, embeds)
+			if shared := sharedCode(options, shards[dir], shardEnums, shardEmbeds); len(shared) > 0 {
+				add(filepath.Join(dir, sharedFile), shared)
+			}
+		}
+	} else {
+		for _, table := range tables {
+			baseName := strings.TrimPrefix(table.Name, table.Prefix)
+			add(baseName+".go", tableCode(options, enums, table))
+			if options.GenHookStubs {
+				addStub(baseName+hookFileSuffix, []jen.Code{goHookStubs(options, table)})
 			}
 		}
 
+		if shared := sharedCode(options, tables, enums, embeds); len(shared) > 0 {
+			add(sharedFile, shared)
+		}
+	}
+
+	if options.GenRepositoryRegistry && !options.GenRepository {
+		return nil, nil, ErrRepositoryRequired
+	}
+	if options.GenRepositoryInterface || options.GenRepository {
+		if options.ModelImportPath == "" {
+			return nil, nil, ErrModelImportPath
+		}
+		dir, pkg := repositoryDir(options)
+		repository := func(table *Table) []jen.Code {
+			if options.GenRepository {
+				if sqlRepository(options) {
+					return []jen.Code{goRepositoryInterface(options, table), goSqlRepositoryImpl(options, table)}
+				}
+				return []jen.Code{goRepositoryInterface(options, table), goRepositoryImpl(options, table)}
+			}
+			return []jen.Code{goRepositoryInterface(options, table)}
+		}
 		if options.ModelSingleFile {
-			f := jen.NewFile(pkgName)
-			f.HeaderComment(headerComment)
+			cs := make([]jen.Code, 0, len(tables)*2+1)
 			for _, table := range tables {
-				f.Add(table.goStatement)
-				f.Line()
+				cs = append(cs, repository(table)...)
 			}
-			err := f.Save(filepath.Join(options.ModelDir, "model.go"))
-			if err != nil {
-				return err
+			if options.GenRepositoryRegistry {
+				cs = append(cs, goRepositoryRegistry(options, tables))
 			}
+			addPkg(pkg, filepath.Join(dir, "repository.go"), cs)
 		} else {
 			for _, table := range tables {
-				f := jen.NewFile(pkgName)
-				f.HeaderComment(headerComment)
-				f.Add(table.goStatement)
-				fileName := fmt.Sprint(strings.TrimPrefix(table.Name, table.Prefix), ".go")
-				err := f.Save(filepath.Join(options.ModelDir, fileName))
-				if err != nil {
-					return err
-				}
+				addPkg(pkg, filepath.Join(dir, strings.TrimPrefix(table.Name, table.Prefix)+".go"), repository(table))
+			}
+			if options.GenRepositoryRegistry {
+				addPkg(pkg, filepath.Join(dir, "registry.go"), []jen.Code{goRepositoryRegistry(options, tables)})
 			}
 		}
 	}
 
-	if options.Verbose {
-		l.Println("Done")
+	if options.GenTests && !options.GormV1 && len(tables) > 0 {
+		add("model_test.go", goModelTests(options, tables))
 	}
 
-	return nil
+	if options.EnumFile != "" && len(enums) > 0 {
+		cs := make([]jen.Code, 0, len(enums))
+		for _, e := range enums {
+			cs = append(cs, goEnum(options, e))
+		}
+		add(options.EnumFile, cs)
+	}
+
+	if options.ConstantsFile != "" {
+		cs := make([]jen.Code, 0, len(tables))
+		for _, table := range tables {
+			cs = append(cs, goConstants(options, goStructName(table), table)...)
+		}
+		if c := goSequenceConstants(options); c != nil {
+			cs = append(cs, c)
+		}
+		if len(cs) > 0 {
+			add(options.ConstantsFile, cs)
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrDuplicateFile, strings.Join(duplicates, ", "))
+	}
+	return files, stubs, nil
+}
+
+func newFile(options *Options, pkgName, headerComment string) *jen.File {
+	f := jen.NewFile(pkgName)
+	if options.LicenseHeader != "" {
+		for _, line := range strings.Split(strings.TrimRight(options.LicenseHeader, "\n"), "\n") {
+			f.HeaderComment(strings.TrimRight(line, "\r"))
+		}
+	}
+	f.HeaderComment(headerComment)
+	if pkg := nullablePackage(options); pkg != "" {
+		f.ImportName(pkg, packageName(pkg))
+	}
+	return f
+}
+
+// prefixGroups tables grouped by filter prefix, group name is the prefix without underscores, e.g. auth of auth_,
+// tables without prefix are grouped as model, names are in order of first table. Returns ErrDuplicateFile if
+// prefixes have the same group name, e.g. model_ and tables without prefix, or the group file is a test file
+func prefixGroups(tables []*Table) (map[string][]*Table, []string, error) {
+	groups := make(map[string][]*Table)
+	prefixes := make(map[string]string)
+	names := make([]string, 0, 4)
+	for _, table := range tables {
+		name := strings.Trim(table.Prefix, "_")
+		if name == "" {
+			name = "model"
+		}
+		if strings.HasSuffix(name, "_test") {
+			return nil, nil, fmt.Errorf("%w: %s.go of prefix %s is a test file", ErrDuplicateFile, name, table.Prefix)
+		}
+		if prefix, ok := prefixes[name]; !ok {
+			prefixes[name] = table.Prefix
+			names = append(names, name)
+		} else if prefix != table.Prefix {
+			return nil, nil, fmt.Errorf("%w: %s.go of prefixes %q and %q", ErrDuplicateFile, name, prefix, table.Prefix)
+		}
+		groups[name] = append(groups[name], table)
+	}
+	return groups, names, nil
+}
+
+// sharedCode code shared by all tables, e.g. shared enums
+func sharedCode(options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) []jen.Code {
+	cs := make([]jen.Code, 0, len(enums)+len(embeds)+2)
+	if options.BaseStruct != "" {
+		if c := goBaseStruct(options, tables); c != nil {
+			cs = append(cs, c)
+		}
+	}
+	for _, g := range embeds {
+		cs = append(cs, goEmbedStruct(options, g))
+	}
+	for _, e := range enums {
+		if e.shared() && options.EnumFile == "" {
+			cs = append(cs, goEnum(options, e))
+		}
+	}
+	if options.GenFieldMeta {
+		cs = append(cs, goFieldMetaType())
+	}
+	if options.GenTableNameFunc {
+		cs = append(cs, jen.Comment("TableNameFunc returns table name of models, override it to change table names at runtime, e.g. tenant prefix").Line().
+			Var().Id("TableNameFunc").Op("=").Func().Params(jen.Id("base").String()).String().Block(
+			jen.Return(jen.Id("base")),
+		))
+	}
+	if options.GenInit && len(tables) > 0 {
+		cs = append(cs, goInit(options, tables))
+	}
+	if options.GenIdentifiable && len(tables) > 0 {
+		cs = append(cs, goIdentifiableInterface())
+	}
+	if options.ConstantsFile == "" {
+		if c := goSequenceConstants(options); c != nil {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// tableCode code of table, enums only used by the table comes first unless routed to options.EnumFile
+func tableCode(options *Options, enums []*enum, table *Table) []jen.Code {
+	cs := make([]jen.Code, 0, 2)
+	for _, e := range enums {
+		if !e.shared() && e.tables[0] == table && options.EnumFile == "" {
+			cs = append(cs, goEnum(options, e))
+		}
+	}
+	return append(cs, table.goStatement)
+}
+
+// columnRef flattened column of all tables, used by html report column search
+type columnRef struct {
+	Table  *Table
+	Field  *Field
+	Search string
+}
+
+func columnIndex(tables []*Table) []*columnRef {
+	index := make([]*columnRef, 0, len(tables)*8)
+	for _, table := range tables {
+		for _, field := range table.Fields {
+			index = append(index, &columnRef{
+				Table:  table,
+				Field:  field,
+				Search: strings.ToLower(strings.Join([]string{table.Name, field.Field, field.Comment}, " ")),
+			})
+		}
+	}
+	return index
 }
 
 func DbStruct(options *Options) ([]*Table, error) {
+	return DbStructContext(context.Background(), options)
+}
+
+// DbStructContext DbStruct, stops early with ctx.Err() when ctx is cancelled
+func DbStructContext(ctx context.Context, options *Options) ([]*Table, error) {
+	if options.DatabaseURL != "" {
+		if err := parseDatabaseURL(options); err != nil {
+			return nil, err
+		}
+	}
+
+	var s strutter
 	switch options.DbType {
 	case DbTypeMySQL:
-		return new(mysql).dbStruct(options)
+		s = new(mysql)
 	case DbTypePostgreSQL:
-		return new(postgresql).dbStruct(options)
+		s = new(postgresql)
+	case DbTypeSpanner:
+		s = new(spanner)
+	case DbTypeRedshift:
+		s = &postgresql{redshift: true}
+	case DbTypeSQLite:
+		s = new(sqlite)
+	case DbTypeOracle:
+		s = new(oracle)
+	case DbTypeSQLServer:
+		s = new(sqlserver)
+	default:
+		return nil, ErrTypeNotSupported
+	}
+	if options.SqlFile != "" {
+		s = new(ddlFile)
+	}
+
+	tables, err := s.dbStruct(ctx, options)
+	if err != nil {
+		return nil, err
 	}
-	return nil, ErrTypeNotSupported
+
+	resolveTypeOverrides(options, tables)
+	resolveTableConfigs(options, tables)
+	if err = resolveUnknownTypes(options, tables); err != nil {
+		return nil, err
+	}
+
+	if options.StrictTypes {
+		if err = checkStrictTypes(options, tables); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func goStructName(table *Table) string {
+	if table.goName != "" {
+		return table.goName
+	}
+	return TitleCase(strings.TrimPrefix(table.Name, table.Prefix))
 }
 
 func goStruct(options *Options, table *Table) {
-	name := TitleCase(strings.TrimPrefix(table.Name, table.Prefix))
+	name := goStructName(table)
 	c := jen.
 		Commentf("%s table: %s", name, table.Name).Line()
 
 	if table.Comment != "" {
 		c = c.Comment(OneLine(table.Comment)).Line()
 	}
+	if table.historyOf != nil {
+		c = c.Comment(historyComment(table)).Line()
+	}
+
+	c = c.Type().Id(name).Struct(goFields(options, table)...)
 
-	c = c.Type().Id(name).Struct(goFields(options, table.Fields)...)
+	if table.historyOf != nil {
+		c = c.Line().Line().Add(goHistorySnapshot(options, name, table))
+	}
 
-	if table.Prefix != "" {
+	// gorm naming strategy doesn't derive folded upper case table name
+	if table.Prefix != "" || table.goName != "" || options.GenTableNameFunc {
+		tableName := jen.Lit(fmt.Sprint(table.Name))
+		if options.GenTableNameFunc {
+			tableName = jen.Id("TableNameFunc").Call(tableName)
+		}
 		c = c.Line().Line().
 			Commentf("TableName set table of %v, ref document see https://gorm.io/docs/conventions.html", table.Name).Line().
-			Func().Params(jen.Id(name)).Id("TableName").Return(jen.String()).Block(
-			jen.Return(jen.Lit(fmt.Sprint(table.Name))),
+			Func().Params(jen.Id(receiverName(options, name, "")).Add(receiverType(options, name))).Id("TableName").Params().String().Block(
+			jen.Return(tableName),
 		)
 	}
 
+	if options.GenSchemaMethod {
+		schema := options.SchemaName
+		if schema == "" {
+			schema = table.Schema
+		}
+		c = c.Line().Line().
+			Commentf("Schema returns schema of %v", table.Name).Line().
+			Func().Params(jen.Id(receiverName(options, name, "")).Add(receiverType(options, name))).Id("Schema").Params().String().Block(
+			jen.Return(jen.Lit(schema)),
+		)
+	}
+
+	if options.GenFieldMeta {
+		c = c.Line().Line().Add(goFieldMeta(name, table.Fields))
+	}
+
+	if options.GenDocMap {
+		if docs := goFieldDocs(name, table.Fields); docs != nil {
+			c = c.Line().Line().Add(docs)
+		}
+	}
+
+	if options.GenPrimaryKeyMethod {
+		if pk := goPrimaryKeyMethod(options, name, table.Fields); pk != nil {
+			c = c.Line().Line().Add(pk)
+		}
+	}
+
+	if options.GenIdentifiable {
+		if id := goIdentifiable(options, name, table); id != nil {
+			c = c.Line().Line().Add(id)
+		}
+	}
+
+	if options.GenFactory {
+		c = c.Line().Line().Add(goFactory(options, name, table))
+	}
+
+	if options.GenSortHelpers {
+		if sorts := goSortHelpers(options, name, table); sorts != nil {
+			c = c.Line().Line().Add(sorts)
+		}
+	}
+
+	if options.GenScopes {
+		if scopes := goScopes(options, name, table); scopes != nil {
+			c = c.Line().Line().Add(scopes)
+		}
+	}
+
+	if options.ConstantsFile == "" {
+		for _, constants := range goConstants(options, name, table) {
+			c = c.Line().Line().Add(constants)
+		}
+	}
+
+	if options.GenMutex {
+		c = c.Line().Line().Add(goAccessors(options, name, table))
+	}
+
+	if options.GenJSONMarshaler && options.GenJsonTag {
+		if m := goJSONMarshaler(options, name, table); m != nil {
+			c = c.Line().Line().Add(m)
+		}
+	}
+
+	if options.GenPIIFieldsMethod {
+		if pii := goPIIFieldsMethod(options, name, table); pii != nil {
+			c = c.Line().Line().Add(pii)
+		}
+	}
+
 	table.GoStruct = c.GoString()
 	table.goStatement = c
 }
 
-func goFields(options *Options, fields []*Field) []jen.Code {
-	cs := make([]jen.Code, 0, len(fields))
+// receiverName receiver variable name of methods of type name, fallback is used if options.ReceiverName is not set
+func receiverName(options *Options, name, fallback string) string {
+	switch options.ReceiverName {
+	case "":
+		return fallback
+	case ReceiverShort:
+		return strings.ToLower(name[:1])
+	}
+	return options.ReceiverName
+}
+
+// receiverType receiver type of methods of models, pointer if model has mutex which must not be copied
+func receiverType(options *Options, name string) *jen.Statement {
+	if options.GenMutex {
+		return jen.Op("*").Id(name)
+	}
+	return jen.Id(name)
+}
+
+// goPrimaryKeyMethod returns `PrimaryKey() any` method, composite primary key returns as slice
+func goPrimaryKeyMethod(options *Options, name string, fields []*Field) jen.Code {
+	pks := primaryKeys(fields)
+	if len(pks) == 0 {
+		return nil
+	}
+
+	recv := receiverName(options, name, "m")
+	var value jen.Code
+	if len(pks) == 1 {
+		value = jen.Id(recv).Dot(goFieldName(pks[0]))
+	} else {
+		values := make([]jen.Code, 0, len(pks))
+		for _, f := range pks {
+			values = append(values, jen.Id(recv).Dot(goFieldName(f)))
+		}
+		value = jen.Index().Id("any").Values(values...)
+	}
+
+	return jen.Commentf("PrimaryKey returns primary key value of %v", name).Line().
+		Func().Params(jen.Id(recv).Add(receiverType(options, name))).Id("PrimaryKey").Params().Id("any").Block(
+		jen.Return(value),
+	)
+}
+
+// orderFields returns fields sorted by order, db order is kept for same rank
+func orderFields(order string, fields []*Field) []*Field {
+	sorted := make([]*Field, len(fields))
+	copy(sorted, fields)
+
+	switch order {
+	case FieldOrderAlpha:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Field < sorted[j].Field
+		})
+	case FieldOrderPkFirst:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Key == "PRI" && sorted[j].Key != "PRI"
+		})
+	}
+	return sorted
+}
+
+// columnOption lookup per column option, key is `table.column` or `column`
+func columnOption(m map[string]string, table *Table, field *Field) (string, bool) {
+	if v, ok := m[fmt.Sprint(table.Name, ".", field.Field)]; ok {
+		return v, true
+	}
+	v, ok := m[field.Field]
+	return v, ok
+}
+
+// columnIn returns true if column in list, item of list is `table.column` or `column`
+func columnIn(list []string, table *Table, field *Field) bool {
+	name := fmt.Sprint(table.Name, ".", field.Field)
+	for _, it := range list {
+		if it == name || it == field.Field {
+			return true
+		}
+	}
+	return false
+}
+
+func primaryKeys(fields []*Field) []*Field {
+	pks := make([]*Field, 0, 1)
 	for _, f := range fields {
-		c := jen.Id(TitleCase(f.Field))
-		if f.Nullable {
-			c = c.Op("*")
+		if f.Key == "PRI" {
+			pks = append(pks, f)
 		}
-		c = goType(options, f, c)
+	}
+	return pks
+}
 
-		tag := make(map[string]string)
-		if options.GenGormTag {
-			t := fmt.Sprintf(`column:%s;type:%s`, f.Field, f.Type)
-			if f.Default != "" {
-				t += fmt.Sprint(";default:", f.Default)
-			}
-			if !f.Nullable {
-				t += ";not null"
-			}
-			if f.Key == "PRI" {
-				t += ";primary_key"
+func goFields(options *Options, table *Table) []jen.Code {
+	cs := make([]jen.Code, 0, len(table.Fields)+1)
+	base := baseField(options, table)
+	if base != nil {
+		cs = append(cs, jen.Id(options.BaseStruct))
+	}
+	cs = append(cs, goConfigEmbeds(options, table)...)
+
+	embedded := make(map[*embedGroup]bool)
+	for _, f := range orderFields(options.FieldOrder, table.Fields) {
+		if f == base {
+			continue
+		}
+		if f.embed != nil {
+			if !embedded[f.embed] {
+				embedded[f.embed] = true
+				cs = append(cs, goEmbedField(options, f.embed))
 			}
+			continue
+		}
+		cs = append(cs, goField(options, table, f))
+	}
 
-			tag["gorm"] = t
+	cs = append(cs, goRelationFields(options, table)...)
+	cs = append(cs, goManyToManyFields(options, table)...)
+	cs = append(cs, goHasManyFields(options, table)...)
+	if options.GenMutex {
+		tag := map[string]string{"json": "-", "gorm": "-"}
+		if options.GenBoilTags {
+			tag["boil"], tag["toml"], tag["yaml"] = "-", "-", "-"
 		}
-		if options.GenJsonTag {
-			tag["json"] = CamelCase(f.Field)
+		cs = append(cs, jen.Line().Id("mu").Qual("sync", "RWMutex").Tag(tag))
+	}
+	return cs
+}
+
+func goField(options *Options, table *Table, f *Field) jen.Code {
+	c := goFieldType(options, f, jen.Id(goFieldName(f)))
+	if f.column != nil {
+		f = f.column
+	}
+
+	tag := make(map[string]string)
+	generatedTags(options, table, f, tag)
+
+	for k, v := range f.userTags {
+		if _, ok := tag[k]; !ok {
+			tag[k] = v
+		}
+	}
+	for k, v := range f.configTags {
+		if _, ok := tag[k]; !ok {
+			tag[k] = v
 		}
+	}
 
-		if len(tag) > 0 {
-			c.Tag(tag)
+	if len(tag) > 0 {
+		for k, v := range tag {
+			tag[k] = escapeTagValue(v)
 		}
+		c.Tag(tag)
+	}
+
+	comment := OneLine(f.Comment)
+	deprecated, isDeprecated := deprecatedComment(options, comment)
+	if isDeprecated {
+		comment = ""
+	}
+	if options.SequenceComment && f.Sequence != "" {
+		comment = strings.TrimSpace(fmt.Sprintf("%s sequence: %s", comment, f.Sequence))
+	}
+	if options.GenComputed && f.Generated != "" {
+		comment = strings.TrimSpace(fmt.Sprintf("%s computed: %s", comment, OneLine(f.Generated)))
+	}
+	if options.CollationComment && f.Collation != "" {
+		comment = strings.TrimSpace(fmt.Sprintf("%s collation: %s", comment, f.Collation))
+	}
+	if secretColumn(options, table, f) {
+		comment = strings.TrimSpace(comment + " secret: not serialized")
+	}
+	if comment != "" {
+		c.Comment(comment)
+	}
 
-		if f.Comment != "" {
-			c.Comment(OneLine(f.Comment))
+	if isDeprecated {
+		return jen.Comment("Deprecated: " + deprecated).Line().Add(c)
+	}
+	return c
+}
+
+// jsonTag json tag of field, nullable pointer field is omitempty only if options.JsonOmitEmptyNullable is set
+func jsonTag(options *Options, f *Field) string {
+	tag := jsonName(options, embeddedColumn(f))
+	if jsonString(options, f) {
+		tag += ",string"
+	}
+	if options.JsonOmitEmpty && (options.JsonOmitEmptyNullable || !pointerField(options, f)) {
+		tag += ",omitempty"
+	}
+	return tag
+}
+
+// jsonName json key of name in options.JsonTagStyle
+func jsonName(options *Options, name string) string {
+	name = foldName(options, name)
+	switch options.JsonTagStyle {
+	case JsonTagSnake:
+		return SnakeCase(name)
+	case JsonTagOriginal:
+		return name
+	}
+	return CamelCase(name)
+}
+
+// envTagKey tag key of env and config tags, default env
+func envTagKey(options *Options) string {
+	if options.EnvTagKey != "" {
+		return options.EnvTagKey
+	}
+	return "env"
+}
+
+// envName key of env and config tags in options.EnvTagCase
+func envName(options *Options, name string) string {
+	name = foldName(options, name)
+	switch options.EnvTagCase {
+	case JsonTagCamel:
+		return CamelCase(name)
+	case JsonTagSnake:
+		return SnakeCase(name)
+	case JsonTagOriginal:
+		return name
+	}
+	return strings.ToUpper(SnakeCase(name))
+}
+
+// pointerField returns true if go type of field is pointer
+func pointerField(options *Options, f *Field) bool {
+	return strings.HasPrefix(goFieldType(options, f, jen.Null()).GoString(), "*")
+}
+
+// jsonStringInt returns true if field is int64 or uint64 which encoding/json `,string` applies to,
+// enum, overridden, sql and custom nullable types are excluded
+func jsonStringInt(options *Options, f *Field) bool {
+	if f.GoType != "int64" && f.GoType != "uint64" || f.goEnum != nil {
+		return false
+	}
+	if _, ok := typeOverride(options, f); ok {
+		return false
+	}
+	if f.Nullable {
+		if _, name := nullableType(options, f.GoType); name != "" {
+			return false
 		}
+	}
+	return true
+}
 
-		cs = append(cs, c)
+// deprecatedComment returns deprecation message of column comment starts with one of options.DeprecatedPrefixes
+func deprecatedComment(options *Options, comment string) (string, bool) {
+	prefixes := options.DeprecatedPrefixes
+	if prefixes == nil {
+		prefixes = defaultDeprecatedPrefixes
 	}
 
-	return cs
+	for _, prefix := range prefixes {
+		if prefix == "" || len(comment) < len(prefix) || !strings.EqualFold(comment[:len(prefix)], prefix) {
+			continue
+		}
+		msg := strings.TrimLeft(comment[len(prefix):], " :-")
+		if msg == "" {
+			msg = "column is deprecated"
+		}
+		return msg, true
+	}
+	return "", false
+}
+
+// goFieldType add field type, nullable field type follows options.NullableStrategy
+func goFieldType(options *Options, field *Field, c *jen.Statement) *jen.Statement {
+	// nullable overridden type is pointer, nil slice is null
+	if v, ok := typeOverride(options, field); ok && field.goEnum == nil {
+		if field.Nullable && !strings.HasPrefix(v, "[]") {
+			c = c.Op("*")
+		}
+		return goType(options, field, c)
+	}
+
+	if field.Nullable && field.GoType == "bool" && options.NullableBool != "" {
+		return c.Qual(qualifiedType(options.NullableBool))
+	}
+
+	if field.Nullable && field.goEnum == nil {
+		if path, name := nullableType(options, field.GoType); name != "" {
+			return c.Qual(path, name)
+		}
+	}
+
+	// nil interface is null
+	if field.Nullable && field.GoType != "interface{}" {
+		c = c.Op("*")
+	}
+	return goType(options, field, c)
 }
 
 func goType(options *Options, field *Field, c *jen.Statement) *jen.Statement {
-	switch field.GoType {
+	if field.goEnum != nil {
+		return c.Id(field.goEnum.Name)
+	}
+	if v, ok := typeOverride(options, field); ok {
+		return goTypeName(options, v, c)
+	}
+	return goTypeName(options, field.GoType, c)
+}
+
+// goTypeName add go type of name, qualified name like github.com/shopspring/decimal.Decimal is imported,
+// unknown type falls back to interface{}
+func goTypeName(options *Options, name string, c *jen.Statement) *jen.Statement {
+	switch name {
 	case "int":
 		return c.Int()
 	case "uint":
@@ -241,9 +1464,14 @@ func goType(options *Options, field *Field, c *jen.Statement) *jen.Statement {
 		return c.Int64()
 	case "uint64":
 		return c.Uint64()
+	case "bool":
+		return c.Bool()
 	case "string":
 		return c.String()
 	case "time.Time":
+		if options.TimeType != "" {
+			return c.Qual(qualifiedType(options.TimeType))
+		}
 		return c.Qual("time", "Time")
 	case "float32":
 		return c.Float32()
@@ -251,9 +1479,24 @@ func goType(options *Options, field *Field, c *jen.Statement) *jen.Statement {
 		return c.Float64()
 	case "[]byte":
 		return c.Op("[]").Byte()
+	case "interface{}":
+		return c.Interface()
 	}
 
-	panic(fmt.Sprintf("unknow gotype: %v", field.GoType))
+	if strings.HasPrefix(name, "[]") {
+		return goTypeName(options, name[2:], c.Index())
+	}
+	if strings.HasPrefix(name, "*") {
+		return goTypeName(options, name[1:], c.Op("*"))
+	}
+	if strings.Contains(name, ".") {
+		return c.Qual(qualifiedType(name))
+	}
+
+	if options.Verbose {
+		l.Printf("unknown go type %q, fallback to interface{}", name)
+	}
+	return c.Interface()
 }
 
 func pkgerReadString(filename string) string {
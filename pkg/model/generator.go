@@ -0,0 +1,118 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// names of built-in generators
+const (
+	GeneratorHtml      = "html"
+	GeneratorGo        = "go"
+	GeneratorMermaid   = "mermaid"
+	GeneratorChangeLog = "changelog"
+	GeneratorMapping   = "mapping"
+	GeneratorMigration = "migration"
+	GeneratorMarkdown  = "markdown"
+	GeneratorJson      = "json"
+	GeneratorOpenApi   = "openapi"
+)
+
+var ErrGeneratorNotFound = errors.New("generator not found")
+
+// Generator output generator of tables, go structs of tables are generated before it's called,
+// see Table.GoStruct. Register with RegisterGenerator and enable it by Options.Generators
+type Generator interface {
+	Generate(options *Options, tables []*Table) error
+}
+
+// GeneratorFunc adapter of func as Generator
+type GeneratorFunc func(options *Options, tables []*Table) error
+
+func (f GeneratorFunc) Generate(options *Options, tables []*Table) error {
+	return f(options, tables)
+}
+
+// defaultGenerators generators run if Options.Generators is empty, each does nothing if its output is not set
+var defaultGenerators = []string{GeneratorHtml, GeneratorGo, GeneratorMermaid, GeneratorChangeLog, GeneratorMapping, GeneratorMigration,
+	GeneratorMarkdown, GeneratorJson, GeneratorOpenApi}
+
+var generators = map[string]Generator{
+	GeneratorHtml: GeneratorFunc(writeHtml),
+	GeneratorGo:   modelsGenerator{},
+	GeneratorMermaid: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.MermaidFile == "" {
+			return nil
+		}
+		return writeMermaid(options, tables)
+	}),
+	GeneratorChangeLog: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.ChangeLogFile == "" {
+			return nil
+		}
+		return writeChangeLog(options, tables)
+	}),
+	GeneratorMapping: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.MappingFile == "" {
+			return nil
+		}
+		return writeMapping(options, tables)
+	}),
+	GeneratorMigration: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.MigrationDir == "" {
+			return nil
+		}
+		return writeMigration(options, tables)
+	}),
+	GeneratorMarkdown: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.MarkdownFile == "" {
+			return nil
+		}
+		return writeMarkdown(options, tables)
+	}),
+	GeneratorJson: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.JsonFile == "" {
+			return nil
+		}
+		return DumpTables(options.JsonFile, tables)
+	}),
+	GeneratorOpenApi: GeneratorFunc(func(options *Options, tables []*Table) error {
+		if options.OpenApiFile == "" {
+			return nil
+		}
+		return writeOpenApi(options, tables)
+	}),
+}
+
+// preparedGenerator generator reusing tables prepared by GenerateContext, built-in generators only
+type preparedGenerator interface {
+	generatePrepared(ctx context.Context, options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) error
+}
+
+// modelsGenerator go generator, see writeModels
+type modelsGenerator struct{}
+
+func (modelsGenerator) Generate(options *Options, tables []*Table) error {
+	enums, embeds, err := prepare(context.Background(), options, tables)
+	if err != nil {
+		return err
+	}
+	return writeModels(context.Background(), options, tables, enums, embeds)
+}
+
+func (modelsGenerator) generatePrepared(ctx context.Context, options *Options, tables []*Table, enums []*enum, embeds []*embedGroup) error {
+	return writeModels(ctx, options, tables, enums, embeds)
+}
+
+// RegisterGenerator register generator by name, usually called in init() of the package providing it,
+// it panics if name is registered twice
+func RegisterGenerator(name string, g Generator) {
+	if g == nil {
+		panic("register nil generator " + name)
+	}
+	if _, ok := generators[name]; ok {
+		panic(fmt.Sprintf("generator %s registered twice", name))
+	}
+	generators[name] = g
+}
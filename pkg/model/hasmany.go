@@ -0,0 +1,121 @@
+package model
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// hasMany has-many association of table referred by foreign key of another table,
+// has-one if the foreign key column is unique
+type hasMany struct {
+	fk *ForeignKey
+	// table the referring table
+	table  *Table
+	one    bool
+	goName string
+}
+
+// resolveHasMany has-many associations of resolved belongs-to associations, pivot tables are many2many
+func resolveHasMany(tables []*Table) {
+	for _, table := range tables {
+		if isPivot(table) {
+			continue
+		}
+		for _, fk := range table.associationKeys() {
+			if fk.refTable == nil {
+				continue
+			}
+			addHasMany(table, fk)
+		}
+	}
+}
+
+func addHasMany(table *Table, fk *ForeignKey) {
+	ref := fk.refTable
+	names := make(map[string]bool, len(ref.Fields))
+	for _, f := range ref.Fields {
+		names[goFieldName(f)] = true
+	}
+	for _, it := range ref.associationKeys() {
+		if it.refTable != nil {
+			names[it.goName] = true
+		}
+	}
+	for _, m := range ref.many2many {
+		names[m.goName] = true
+	}
+	for _, h := range ref.hasMany {
+		names[h.goName] = true
+	}
+
+	h := &hasMany{fk: fk, table: table, goName: goStructName(table)}
+	h.one = fk.field.Key == "UNI" || fk.field.Key == "PRI" && len(primaryKeys(table.Fields)) == 1
+	if !h.one {
+		h.goName += "s"
+	}
+	for names[h.goName] {
+		h.goName += "Ref"
+	}
+	ref.hasMany = append(ref.hasMany, h)
+}
+
+// goHasManyFields has-many and has-one association fields of table, has-one is pointer
+func goHasManyFields(options *Options, table *Table) []jen.Code {
+	cs := make([]jen.Code, 0, len(table.hasMany))
+	for _, h := range table.hasMany {
+		c := jen.Id(h.goName)
+		if h.one {
+			c.Op("*")
+		} else {
+			c.Index()
+		}
+		c.Id(goStructName(h.table))
+
+		foreignKey, references := goFieldName(h.fk.field), refFieldName(h.fk)
+		tag := map[string]string{
+			"gorm": "foreignKey:" + foreignKey + ";references:" + references,
+		}
+		if options.GormV1 {
+			tag["gorm"] = "foreignkey:" + foreignKey + ";association_foreignkey:" + references
+		}
+		if options.GenJsonTag {
+			tag["json"] = associationJsonName(options, h.goName) + ",omitempty"
+		}
+		cs = append(cs, c.Tag(tag))
+	}
+	return cs
+}
+
+// relationEdge foreign key of html report, Out is foreign key of the table, referring the table otherwise
+type relationEdge struct {
+	Column    string
+	Table     string
+	RefColumn string
+	Out       bool
+	Inferred  bool
+}
+
+// relationEdges foreign keys and inferred foreign keys of tables and referring them, by table name
+func relationEdges(tables []*Table) map[string][]*relationEdge {
+	edges := make(map[string][]*relationEdge, len(tables))
+	for _, table := range tables {
+		for _, fk := range table.associationKeys() {
+			inferred := !containsKey(table.ForeignKeys, fk)
+			edges[table.Name] = append(edges[table.Name], &relationEdge{
+				Column: fk.Column, Table: fk.RefTable, RefColumn: fk.RefColumn, Out: true, Inferred: inferred,
+			})
+			edges[fk.RefTable] = append(edges[fk.RefTable], &relationEdge{
+				Column: fk.RefColumn, Table: table.Name, RefColumn: fk.Column, Inferred: inferred,
+			})
+		}
+	}
+	return edges
+}
+
+func containsKey(fks []*ForeignKey, fk *ForeignKey) bool {
+	for _, it := range fks {
+		if it == fk {
+			return true
+		}
+	}
+	return false
+}
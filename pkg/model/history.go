@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// resolveHistories pair history tables `<table><options.HistorySuffix>` with their tables, e.g. user and user_history
+func resolveHistories(options *Options, tables []*Table) {
+	byName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		table.historyOf = nil
+		byName[table.Name] = table
+	}
+	if options.HistorySuffix == "" {
+		return
+	}
+
+	for _, table := range tables {
+		name := strings.TrimSuffix(table.Name, options.HistorySuffix)
+		if name == table.Name {
+			continue
+		}
+		if current, ok := byName[name]; ok {
+			table.historyOf = current
+		}
+	}
+}
+
+// historyComment note the temporal table pair of history table
+func historyComment(table *Table) string {
+	return fmt.Sprintf("history of %s, temporal table pair: %s / %s", goStructName(table.historyOf), table.historyOf.Name, table.Name)
+}
+
+// goHistorySnapshot `Snapshot()` method of history model returns the model of its table populated with shared columns,
+// shared columns have the same name and go type, folded columns are not copied
+func goHistorySnapshot(options *Options, name string, table *Table) jen.Code {
+	current := table.historyOf
+	currentName := goStructName(current)
+	recv := receiverName(options, name, "m")
+
+	fields := make(map[string]string, len(table.Fields))
+	for _, f := range table.Fields {
+		if f.embed == nil && f != baseField(options, table) {
+			fields[f.Field] = goFieldType(options, f, jen.Null()).GoString()
+		}
+	}
+
+	values := jen.Dict{}
+	if options.BaseStruct != "" && baseField(options, table) != nil && baseField(options, current) != nil {
+		values[jen.Id(options.BaseStruct)] = jen.Id(recv).Dot(options.BaseStruct)
+	}
+	for _, f := range current.Fields {
+		if f.embed != nil || f == baseField(options, current) {
+			continue
+		}
+		if t, ok := fields[f.Field]; ok && t == goFieldType(options, f, jen.Null()).GoString() {
+			values[jen.Id(goFieldName(f))] = jen.Id(recv).Dot(goFieldName(f))
+		}
+	}
+
+	return jen.Commentf("Snapshot returns %s of the history version", currentName).Line().
+		Func().Params(jen.Id(recv).Add(receiverType(options, name))).Id("Snapshot").Params().Id(currentName).Block(
+		jen.Return(jen.Id(currentName).Values(values)),
+	)
+}
@@ -0,0 +1,37 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// hookFileSuffix suffix of editable hook stub files, existing files are kept on regeneration
+const hookFileSuffix = "_hook.go"
+
+// gormHooks gorm lifecycle hooks
+var gormHooks = []string{
+	"BeforeSave", "AfterSave",
+	"BeforeCreate", "AfterCreate",
+	"BeforeUpdate", "AfterUpdate",
+	"BeforeDelete", "AfterDelete",
+	"AfterFind",
+}
+
+// goHookStubs empty gorm hook methods of table model
+func goHookStubs(options *Options, table *Table) *jen.Statement {
+	gormPkg := gormPackage(options)
+	name := goStructName(table)
+	recv := receiverName(options, name, "m")
+	tx := "tx"
+	if recv == tx {
+		tx = "db"
+	}
+	c := jen.Null()
+	for i, hook := range gormHooks {
+		if i > 0 {
+			c.Line().Line()
+		}
+		c.Commentf("%s gorm hook of %s", hook, name).Line().
+			Func().Params(jen.Id(recv).Op("*").Id(name)).Id(hook).Params(jen.Id(tx).Op("*").Qual(gormPkg, "DB")).Error().Block(
+			jen.Return(jen.Nil()),
+		)
+	}
+	return c
+}
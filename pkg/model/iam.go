@@ -0,0 +1,86 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+var ErrIAMAuthNotSupported = errors.New("iam auth not supported, build with tag `aws`")
+
+// IAMAuth config of AWS RDS IAM authentication, a short-lived auth token is used as the password
+type IAMAuth struct {
+	Region string
+	// Endpoint host:port of the db instance, default parsed from dsn
+	Endpoint string
+	// User db user, default parsed from dsn
+	User string
+}
+
+// buildIAMAuthToken generate auth token of AWS RDS, set by the `aws` build tag
+var buildIAMAuthToken = func(auth *IAMAuth) (string, error) {
+	return "", ErrIAMAuthNotSupported
+}
+
+// iamDsn returns dsn with the password replaced by AWS RDS IAM auth token
+func iamDsn(options *Options) (string, error) {
+	auth := *options.IAMAuth
+
+	switch options.DbType {
+	case DbTypeMySQL:
+		cfg, err := driver.ParseDSN(options.Dsn)
+		if err != nil {
+			return "", err
+		}
+		if auth.Endpoint == "" {
+			auth.Endpoint = cfg.Addr
+		}
+		if auth.User == "" {
+			auth.User = cfg.User
+		}
+
+		token, err := buildIAMAuthToken(&auth)
+		if err != nil {
+			return "", err
+		}
+		cfg.User = auth.User
+		cfg.Passwd = token
+		cfg.AllowCleartextPasswords = true
+		if cfg.TLSConfig == "" {
+			cfg.TLSConfig = "true"
+		}
+		return cfg.FormatDSN(), nil
+	case DbTypePostgreSQL:
+		u, err := url.Parse(options.Dsn)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("iam auth requires postgres url dsn, e.g. postgres://user@host:5432/db")
+		}
+		if auth.Endpoint == "" {
+			auth.Endpoint = u.Host
+			if _, _, err = net.SplitHostPort(u.Host); err != nil {
+				auth.Endpoint = net.JoinHostPort(u.Host, "5432")
+			}
+		}
+		if auth.User == "" && u.User != nil {
+			auth.User = u.User.Username()
+		}
+
+		token, err := buildIAMAuthToken(&auth)
+		if err != nil {
+			return "", err
+		}
+		u.User = url.UserPassword(auth.User, token)
+		if !strings.Contains(u.RawQuery, "sslmode=") {
+			q := u.Query()
+			q.Set("sslmode", "require")
+			u.RawQuery = q.Encode()
+		}
+		return u.String(), nil
+	}
+
+	return "", ErrTypeNotSupported
+}
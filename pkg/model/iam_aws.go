@@ -0,0 +1,20 @@
+//go:build aws
+// +build aws
+
+package model
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+)
+
+func init() {
+	buildIAMAuthToken = func(auth *IAMAuth) (string, error) {
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(auth.Region)})
+		if err != nil {
+			return "", err
+		}
+		return rdsutils.BuildAuthToken(auth.Endpoint, auth.Region, auth.User, sess.Config.Credentials)
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goIdentifiableInterface `Identifiable` interface satisfied by models of single column primary key
+func goIdentifiableInterface() jen.Code {
+	return jen.Comment("Identifiable models of single column primary key").Line().
+		Type().Id("Identifiable").Interface(
+		jen.Id("GetID").Params().Interface(),
+		jen.Id("SetID").Params(jen.Id("id").Interface()),
+	)
+}
+
+// goIdentifiable `GetID()` and `SetID(id)` methods of Identifiable, nil for composite or no primary key,
+// SetID panics if id is not of primary key type
+func goIdentifiable(options *Options, name string, table *Table) jen.Code {
+	pks := primaryKeys(table.Fields)
+	if len(pks) != 1 {
+		return nil
+	}
+
+	recv := receiverName(options, name, "m")
+	field := goFieldName(pks[0])
+	return jen.Commentf("GetID returns primary key %s of %s", pks[0].Field, name).Line().
+		Func().Params(jen.Id(recv).Add(receiverType(options, name))).Id("GetID").Params().Interface().Block(
+		jen.Return(jen.Id(recv).Dot(field)),
+	).Line().Line().
+		Commentf("SetID set primary key %s of %s", pks[0].Field, name).Line().
+		Func().Params(jen.Id(recv).Op("*").Id(name)).Id("SetID").Params(jen.Id("id").Interface()).Block(
+		jen.Id(recv).Dot(field).Op("=").Id("id").Assert(goFieldType(options, pks[0], jen.Null())),
+	)
+}
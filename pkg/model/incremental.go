@@ -0,0 +1,174 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var ErrOutdated = errors.New("generated code is out of date")
+
+// generatedHeaderRegexp timestamp header of generated files, ignored comparing generated code
+var generatedHeaderRegexp = regexp.MustCompile(`(?m)^// code generated by database-struct @.*$`)
+
+var packageClauseRegexp = regexp.MustCompile(`(?m)^package `)
+
+// sameGenerated returns true if generated sources are equal except their timestamp headers
+func sameGenerated(a, b []byte) bool {
+	return bytes.Equal(generatedHeaderRegexp.ReplaceAll(a, nil), generatedHeaderRegexp.ReplaceAll(b, nil))
+}
+
+// saveGenerated save files keyed by names relative to options.ModelDir, unchanged files are kept and generated files
+// no longer in names are removed if options.Incremental, options.Check saves nothing and returns ErrOutdated listing
+// changed and removed files
+func saveGenerated(options *Options, names []string, files map[string][]byte) error {
+	outdated := make([]string, 0)
+	for _, name := range names {
+		file := filepath.Join(options.ModelDir, name)
+		if options.Incremental || options.Check {
+			if b, err := ioutil.ReadFile(file); err == nil && sameGenerated(b, files[name]) {
+				continue
+			}
+		}
+		if options.Check {
+			outdated = append(outdated, name)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, files[name], 0644); err != nil {
+			return err
+		}
+		if options.Verbose && options.Incremental {
+			l.Println("updated", file)
+		}
+	}
+
+	if options.Incremental || options.Check {
+		stale, err := staleGenerated(options.ModelDir, names)
+		if err != nil {
+			return err
+		}
+		for _, name := range stale {
+			if options.Check {
+				outdated = append(outdated, name+" (removed)")
+				continue
+			}
+			file := filepath.Join(options.ModelDir, name)
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+			if options.Verbose {
+				l.Println("removed", file)
+			}
+		}
+	}
+
+	if len(outdated) > 0 {
+		return fmt.Errorf("%w: %s", ErrOutdated, strings.Join(outdated, ", "))
+	}
+	return nil
+}
+
+// staleGenerated go files of dir and its sub dirs with the generated header but not in names, e.g. model of a
+// dropped table, names are relative to dir with slashes. Hook stubs and hand-written files are not generated
+func staleGenerated(dir string, names []string) ([]string, error) {
+	generated := make(map[string]bool, len(names))
+	for _, name := range names {
+		generated[filepath.ToSlash(name)] = true
+	}
+
+	stale := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); generated[rel] {
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if isGenerated(b) {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// isGenerated returns true if the header comments before package clause have the generated header
+func isGenerated(b []byte) bool {
+	if loc := packageClauseRegexp.FindIndex(b); loc != nil {
+		b = b[:loc[0]]
+	}
+	return generatedHeaderRegexp.Match(b)
+}
+
+// printDiff print changes of tables since the snapshot of options.SnapshotFile, or the changelog
+func printDiff(options *Options, tables []*Table, w io.Writer) error {
+	snapshot := snapshotFile(options)
+	if snapshot == "" {
+		return errors.New("snapshot file is required by schema diff")
+	}
+	previous, err := LoadTables(snapshot)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	_, err = io.WriteString(w, diffTables(previous, tables).text())
+	return err
+}
+
+// text plain summary of the diff, e.g. `~ user: + email varchar(64)`
+func (d *schemaDiff) text() string {
+	if d.empty() {
+		return "no schema changes\n"
+	}
+
+	var b strings.Builder
+	for _, t := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", t.Name)
+	}
+	for _, t := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", t.Name)
+	}
+	for _, td := range d.Modified {
+		fmt.Fprintf(&b, "~ %s\n", td.Name)
+		if td.Comment != nil {
+			fmt.Fprintf(&b, "    ~ comment: %q -> %q\n", OneLine(td.Comment[0]), OneLine(td.Comment[1]))
+		}
+		for _, f := range td.Added {
+			fmt.Fprintf(&b, "    + %s %s\n", f.Field, f.Type)
+		}
+		for _, f := range td.Removed {
+			fmt.Fprintf(&b, "    - %s %s\n", f.Field, f.Type)
+		}
+		for _, fd := range td.Modified {
+			fmt.Fprintf(&b, "    ~ %s: %s\n", fd.Field, strings.Join(fd.Changes, ", "))
+		}
+		if len(td.Reordered) > 0 {
+			fmt.Fprintf(&b, "    ~ reordered: %s\n", strings.Join(td.Reordered, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// manyToMany many2many association of table through pivot table
+type manyToMany struct {
+	pivot *Table
+	// own foreign key of pivot referring the table, ref foreign key referring the associated table
+	own, ref *ForeignKey
+	goName   string
+}
+
+// pivotKeys foreign keys of pure pivot table, which has only two columns referring two different generated tables
+func pivotKeys(table *Table) (*ForeignKey, *ForeignKey) {
+	if len(table.Fields) != 2 {
+		return nil, nil
+	}
+	fks := make([]*ForeignKey, 0, 2)
+	for _, fk := range table.associationKeys() {
+		if fk.refTable != nil {
+			fks = append(fks, fk)
+		}
+	}
+	if len(fks) != 2 || fks[0].field == fks[1].field || fks[0].refTable == fks[1].refTable {
+		return nil, nil
+	}
+	return fks[0], fks[1]
+}
+
+// isPivot returns true if table is pure pivot table represented as many2many associations
+func isPivot(table *Table) bool {
+	a, _ := pivotKeys(table)
+	return a != nil
+}
+
+// resolveManyToMany many2many associations of pure pivot tables, called after belongs-to associations resolved
+func resolveManyToMany(tables []*Table) {
+	for _, table := range tables {
+		table.many2many = nil
+	}
+
+	for _, pivot := range tables {
+		a, b := pivotKeys(pivot)
+		if a == nil {
+			continue
+		}
+		addManyToMany(pivot, a, b)
+		addManyToMany(pivot, b, a)
+	}
+}
+
+func addManyToMany(pivot *Table, own, ref *ForeignKey) {
+	table := own.refTable
+	names := make(map[string]bool, len(table.Fields))
+	for _, f := range table.Fields {
+		names[goFieldName(f)] = true
+	}
+	for _, fk := range table.associationKeys() {
+		if fk.refTable != nil {
+			names[fk.goName] = true
+		}
+	}
+	for _, m := range table.many2many {
+		names[m.goName] = true
+	}
+
+	m := &manyToMany{pivot: pivot, own: own, ref: ref, goName: goStructName(ref.refTable) + "s"}
+	for names[m.goName] {
+		m.goName += "Ref"
+	}
+	table.many2many = append(table.many2many, m)
+}
+
+// goManyToManyFields many2many association fields of table
+func goManyToManyFields(options *Options, table *Table) []jen.Code {
+	cs := make([]jen.Code, 0, len(table.many2many))
+	for _, m := range table.many2many {
+		c := jen.Id(m.goName).Index().Id(goStructName(m.ref.refTable))
+
+		tag := map[string]string{
+			"gorm": strings.Join([]string{
+				"many2many:" + m.pivot.Name,
+				"foreignKey:" + refFieldName(m.own),
+				"joinForeignKey:" + goFieldName(m.own.field),
+				"references:" + refFieldName(m.ref),
+				"joinReferences:" + goFieldName(m.ref.field),
+			}, ";"),
+		}
+		if options.GormV1 {
+			tag["gorm"] = "many2many:" + m.pivot.Name + ";jointable_foreignkey:" + m.own.Column +
+				";association_jointable_foreignkey:" + m.ref.Column
+		}
+		if options.GenJsonTag {
+			tag["json"] = associationJsonName(options, m.goName) + ",omitempty"
+		}
+		cs = append(cs, c.Tag(tag))
+	}
+	return cs
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// mappingRow struct field to json key and db column of a model
+type mappingRow struct {
+	Struct string
+	Field  string
+	Json   string
+	Column string
+	GoType string
+	DbType string
+}
+
+var mappingHeader = []string{"struct", "field", "json", "column", "go type", "db type"}
+
+func (r *mappingRow) values() []string {
+	return []string{r.Struct, r.Field, r.Json, r.Column, r.GoType, r.DbType}
+}
+
+// mappingRows mapping of all fields of tables, folded columns are referred via the embedded struct, e.g. Address.City
+func mappingRows(options *Options, tables []*Table) []*mappingRow {
+	rows := make([]*mappingRow, 0, len(tables)*8)
+	for _, table := range tables {
+		name := goStructName(table)
+		for _, f := range table.Fields {
+			field, json := goFieldName(f), goFieldName(f)
+			if options.GenJsonTag {
+				json = jsonName(options, f.Field)
+				if jsonString(options, f) {
+					json += ",string"
+				}
+			}
+			if g := f.embed; g != nil {
+				column := strings.TrimPrefix(f.Field, g.Prefix)
+				field = g.Name + "." + TitleCase(column)
+				if options.GenJsonTag {
+					json = g.jsonName(options) + "." + jsonName(options, column)
+				} else {
+					json = field
+				}
+			}
+
+			if secretColumn(options, table, f) {
+				json = "-"
+			}
+
+			rows = append(rows, &mappingRow{
+				Struct: name,
+				Field:  field,
+				Json:   json,
+				Column: f.Field,
+				GoType: goFieldType(options, f, jen.Null()).GoString(),
+				DbType: f.Type,
+			})
+		}
+	}
+	return rows
+}
+
+// writeMapping write mapping of tables to options.MappingFile, csv if file ext is .csv, markdown table otherwise
+func writeMapping(options *Options, tables []*Table) error {
+	rows := mappingRows(options, tables)
+
+	var b bytes.Buffer
+	if strings.EqualFold(filepath.Ext(options.MappingFile), ".csv") {
+		w := csv.NewWriter(&b)
+		_ = w.Write(mappingHeader)
+		for _, r := range rows {
+			_ = w.Write(r.values())
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(mappingHeader, " | "))
+		fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(mappingHeader)))
+		for _, r := range rows {
+			values := r.values()
+			for i, v := range values {
+				values[i] = "`" + strings.ReplaceAll(v, "|", `\|`) + "`"
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(values, " | "))
+		}
+	}
+
+	return ioutil.WriteFile(options.MappingFile, b.Bytes(), 0600)
+}
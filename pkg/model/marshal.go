@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// goJSONMarshaler `MarshalJSON` method encodes model as is, except nil pointer fields are omitted,
+// pointer fields with omitempty json tags shadow fields of the embedded alias type, nil if there is none
+func goJSONMarshaler(options *Options, name string, table *Table) jen.Code {
+	base := baseField(options, table)
+	fields := make([]jen.Code, 0, len(table.Fields))
+	values := jen.Dict{}
+	recv := receiverName(options, name, "m")
+
+	alias := jen.Id("alias")
+	value := jen.Id("alias").Call(jen.Id(recv))
+	if options.GenMutex {
+		alias = jen.Op("*").Id("alias")
+		value = jen.Parens(jen.Op("*").Id("alias")).Call(jen.Id(recv))
+	}
+	fields = append(fields, alias.Clone())
+	values[jen.Id("alias")] = value
+
+	for _, f := range table.Fields {
+		if f == base || f.embed != nil {
+			continue
+		}
+		field := goFieldName(f)
+		if !pointerField(options, f) || secretColumn(options, table, f) {
+			continue
+		}
+		tag := jsonTag(options, f)
+		if !strings.HasSuffix(tag, ",omitempty") {
+			tag += ",omitempty"
+		}
+		fields = append(fields, goFieldType(options, f, jen.Id(field)).Tag(map[string]string{"json": tag}))
+		values[jen.Id(field)] = jen.Id(recv).Dot(field)
+	}
+	if len(values) == 1 {
+		return nil
+	}
+
+	return jen.Comment("MarshalJSON implements json.Marshaler, nil pointer fields are omitted").Line().
+		Func().Params(jen.Id(recv).Add(receiverType(options, name))).Id("MarshalJSON").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+		jen.Type().Id("alias").Id(name),
+		jen.Return(jen.Qual("encoding/json", "Marshal").Call(jen.Struct(fields...).Values(values))),
+	)
+}
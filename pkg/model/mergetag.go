@@ -0,0 +1,170 @@
+package model
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// builtinTags tag keys of built-in tag generators, never merged from existing files
+var builtinTags = map[string]bool{
+	"gorm":    true,
+	"json":    true,
+	"conform": true,
+	"boil":    true,
+	"toml":    true,
+	"yaml":    true,
+}
+
+// resolveUserTags read field tags of structs in existing go files of options.ModelDir,
+// keys the generator does not manage are kept on the fields of tables, see managedTags
+func resolveUserTags(options *Options, tables []*Table) error {
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			f.userTags = nil
+		}
+	}
+	if !options.MergeTags || options.ModelDir == "" {
+		return nil
+	}
+
+	structs := make(map[string]map[string]map[string]string)
+	err := filepath.Walk(options.ModelDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+
+			fields := make(map[string]map[string]string)
+			for _, field := range st.Fields.List {
+				if field.Tag == nil || len(field.Names) == 0 {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				fields[field.Names[0].Name] = parseTag(tag)
+			}
+			structs[spec.Name.Name] = fields
+			return false
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		fields, ok := structs[goStructName(table)]
+		if !ok {
+			continue
+		}
+		for _, f := range table.Fields {
+			keys, generated := managedTags(options, table, f)
+			for k, v := range fields[goFieldName(f)] {
+				if g, ok := generated[k]; keys[k] || ok && g == v {
+					continue
+				}
+				if f.userTags == nil {
+					f.userTags = make(map[string]string)
+				}
+				f.userTags[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// managedTags tag keys of field written by the generator: built-in keys, env tag, pii tag and keys of enabled
+// tag generators. generated are tags of all registered tag generators and env tag, an existing tag of the
+// same value is not hand-written, so tags of disabled generators are not kept
+func managedTags(options *Options, table *Table, f *Field) (map[string]bool, map[string]string) {
+	keys := make(map[string]bool, len(builtinTags)+len(options.Tags)+2)
+	for k := range builtinTags {
+		keys[k] = true
+	}
+	keys[piiTag(options)] = true
+	if options.GenEnvTag {
+		keys[envTagKey(options)] = true
+	}
+
+	generated := map[string]string{envTagKey(options): envName(options, f.Field)}
+	for _, g := range tagGenerators {
+		for k, v := range g.Tags(options, table, f) {
+			generated[k] = v
+		}
+	}
+	enabled := make([]TagGenerator, 0, len(options.Tags)+len(options.TagGenerators))
+	for _, name := range options.Tags {
+		if g, ok := tagGenerators[name]; ok {
+			enabled = append(enabled, g)
+		}
+	}
+	for _, g := range append(enabled, options.TagGenerators...) {
+		for k := range g.Tags(options, table, f) {
+			keys[k] = true
+		}
+	}
+	return keys, generated
+}
+
+// parseTag parse struct tag to key values, follows reflect.StructTag.Lookup
+func parseTag(tag string) map[string]string {
+	values := make(map[string]string)
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tag = tag[i+1:]
+		values[name] = value
+	}
+	return values
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var typeArgs = regexp.MustCompile(`[(][^)]*[)]`)
+
+// mermaid Mermaid erDiagram of tables, relationships of foreign keys and inferred foreign keys
+func mermaid(tables []*Table) string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range tables {
+		fmt.Fprintf(&b, "    %s {\n", table.Name)
+		for _, f := range table.Fields {
+			fmt.Fprintf(&b, "        %s %s", mermaidType(f.Type), f.Field)
+			if f.Key == "PRI" {
+				b.WriteString(" PK")
+			}
+			if comment := OneLine(f.Comment); comment != "" {
+				fmt.Fprintf(&b, " %q", strings.ReplaceAll(comment, `"`, "'"))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	names := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		names[table.Name] = true
+	}
+	for _, table := range tables {
+		for _, fk := range table.associationKeys() {
+			if names[fk.RefTable] {
+				fmt.Fprintf(&b, "    %s %s %s : %q\n", fk.RefTable, mermaidCardinality(table, fk.Column), table.Name, fk.Column)
+			}
+		}
+	}
+	return b.String()
+}
+
+// mermaidCardinality relationship of referenced table to table of foreign key column, one to many `||--o{`,
+// zero or one to many `|o--o{` of nullable column, one to one `||--||` of unique column
+func mermaidCardinality(table *Table, column string) string {
+	for _, f := range table.Fields {
+		if f.Field != column {
+			continue
+		}
+		if f.Key == "UNI" || f.Key == "PRI" && len(primaryKeys(table.Fields)) == 1 {
+			if f.Nullable {
+				return "|o--o|"
+			}
+			return "||--||"
+		}
+		if f.Nullable {
+			return "|o--o{"
+		}
+	}
+	return "||--o{"
+}
+
+// mermaidType attribute type of column type, mermaid type is a single word, e.g. varchar(64) -> varchar
+func mermaidType(dbType string) string {
+	return strings.Join(strings.Fields(typeArgs.ReplaceAllString(dbType, " ")), "_")
+}
+
+func writeMermaid(options *Options, tables []*Table) error {
+	return ioutil.WriteFile(options.MermaidFile, []byte(mermaid(tables)), 0600)
+}
@@ -0,0 +1,52 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goFieldMetaType `FieldMeta` type shared by the generated field metadata
+func goFieldMetaType() *jen.Statement {
+	return jen.Comment("FieldMeta column metadata of model field").Line().
+		Type().Id("FieldMeta").Struct(
+		jen.Id("Name").String(),
+		jen.Id("Column").String(),
+		jen.Id("Type").String(),
+		jen.Id("GoType").String(),
+		jen.Id("Nullable").Bool(),
+		jen.Id("PrimaryKey").Bool(),
+		jen.Id("Comment").String(),
+	)
+}
+
+// goFieldMeta `var <Name>Fields = []FieldMeta{...}` of fields
+func goFieldMeta(name string, fields []*Field) *jen.Statement {
+	return jen.Commentf("%sFields column metadata of %s", name, name).Line().
+		Var().Id(name + "Fields").Op("=").Index().Id("FieldMeta").ValuesFunc(func(g *jen.Group) {
+		for _, f := range fields {
+			g.Line().Values(
+				jen.Id("Name").Op(":").Lit(goFieldName(f)),
+				jen.Id("Column").Op(":").Lit(f.Field),
+				jen.Id("Type").Op(":").Lit(f.Type),
+				jen.Id("GoType").Op(":").Lit(f.GoType),
+				jen.Id("Nullable").Op(":").Lit(f.Nullable),
+				jen.Id("PrimaryKey").Op(":").Lit(f.Key == "PRI"),
+				jen.Id("Comment").Op(":").Lit(OneLine(f.Comment)),
+			)
+		}
+		g.Line()
+	})
+}
+
+// goFieldDocs `var <Name>FieldDocs = map[string]string{...}` of go field names to column comments,
+// nil if no column has comment
+func goFieldDocs(name string, fields []*Field) jen.Code {
+	docs := make(jen.Dict, len(fields))
+	for _, f := range fields {
+		if comment := OneLine(f.Comment); comment != "" {
+			docs[jen.Lit(goFieldName(f))] = jen.Lit(comment)
+		}
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return jen.Commentf("%sFieldDocs column comments of %s fields", name, name).Line().
+		Var().Id(name + "FieldDocs").Op("=").Map(jen.String()).String().Values(docs)
+}
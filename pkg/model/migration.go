@@ -0,0 +1,142 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// migrationVersion version prefix of golang-migrate files, e.g. 0001 of 0001_init.up.sql
+var migrationVersion = regexp.MustCompile(`^(\d+)_`)
+
+// initMigration existing init migration, e.g. 0001_init.up.sql
+var initMigration = regexp.MustCompile(`^(\d+)_init\.up\.sql$`)
+
+// writeMigration write golang-migrate `NNNN_init.up.sql` creating tables and `NNNN_init.down.sql` dropping them
+// to options.MigrationDir, an existing init migration is rewritten in place, otherwise version follows the
+// existing migrations of the dir
+func writeMigration(options *Options, tables []*Table) error {
+	if err := os.MkdirAll(options.MigrationDir, 0700); err != nil {
+		return err
+	}
+	version, err := initMigrationVersion(options.MigrationDir)
+	if err != nil {
+		return err
+	}
+
+	sorted, cycles := dependencyOrder(tables)
+	up := make([]string, 0, len(sorted)+1)
+	if warnings := cycleWarnings(cycles); len(warnings) > 0 {
+		for _, w := range warnings {
+			l.Println(w)
+		}
+		up = append(up, "-- "+strings.Join(warnings, ", add constraints after creating tables\n-- ")+", add constraints after creating tables")
+	}
+	for _, table := range sorted {
+		ddl := strings.TrimSpace(table.Ddl)
+		if ddl == "" {
+			// db without ddl introspection, e.g. redshift
+			ddl = new(postgresql).tableDdl(table)
+		}
+		if !strings.HasSuffix(ddl, ";") {
+			ddl += ";"
+		}
+		up = append(up, ddl)
+	}
+	down := make([]string, 0, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		down = append(down, fmt.Sprintf("drop table if exists %s;", sqlIdent(options.DbType, sorted[i].Name)))
+	}
+
+	name := filepath.Join(options.MigrationDir, fmt.Sprintf("%04d_init", version))
+	if err = ioutil.WriteFile(name+".up.sql", []byte(strings.Join(up, "\n\n")+"\n"), 0600); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name+".down.sql", []byte(strings.Join(down, "\n")+"\n"), 0600)
+}
+
+// initMigrationVersion version of the existing init migration of dir, or the version after the latest migration,
+// 1 if there is none
+func initMigrationVersion(dir string) (int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if m := initMigration.FindStringSubmatch(f.Name()); m != nil {
+			v, _ := strconv.Atoi(m[1])
+			return v, nil
+		}
+	}
+	version := 0
+	for _, f := range files {
+		if m := migrationVersion.FindStringSubmatch(f.Name()); m != nil {
+			if v, _ := strconv.Atoi(m[1]); v > version {
+				version = v
+			}
+		}
+	}
+	return version + 1, nil
+}
+
+// dependencyOrder tables sorted so referenced tables of foreign keys come first, self references are ignored,
+// cycles are returned as table names, e.g. [a b a], tables in a cycle keep their order
+func dependencyOrder(tables []*Table) (sorted []*Table, cycles [][]string) {
+	byName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+
+	sorted = make([]*Table, 0, len(tables))
+	state := make(map[*Table]int, len(tables)) // 1 visiting, 2 done
+	stack := make([]*Table, 0, 8)
+	var visit func(table *Table)
+	visit = func(table *Table) {
+		state[table] = 1
+		stack = append(stack, table)
+		for _, fk := range table.ForeignKeys {
+			ref, ok := byName[fk.RefTable]
+			if !ok || ref == table {
+				continue
+			}
+			switch state[ref] {
+			case 0:
+				visit(ref)
+			case 1:
+				cycle := make([]string, 0, len(stack)+1)
+				for i := len(stack) - 1; i >= 0; i-- {
+					cycle = append(cycle, stack[i].Name)
+					if stack[i] == ref {
+						break
+					}
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				cycles = append(cycles, append(cycle, ref.Name))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[table] = 2
+		sorted = append(sorted, table)
+	}
+	for _, table := range tables {
+		if state[table] == 0 {
+			visit(table)
+		}
+	}
+	return
+}
+
+// cycleWarnings describe foreign key cycles, e.g. `foreign key cycle: a -> b -> a`
+func cycleWarnings(cycles [][]string) []string {
+	warnings := make([]string, 0, len(cycles))
+	for _, cycle := range cycles {
+		warnings = append(warnings, "foreign key cycle: "+strings.Join(cycle, " -> "))
+	}
+	return warnings
+}
@@ -5,11 +5,23 @@ import "github.com/dave/jennifer/jen"
 type Table struct {
 	Ddl         string
 	Prefix      string
+	Schema      string
 	Name        string
 	Comment     string
 	Fields      []*Field
+	ForeignKeys []*ForeignKey
 	GoStruct    string
 	goStatement *jen.Statement
+	// many2many resolved many2many associations through pivot tables
+	many2many []*manyToMany
+	// hasMany resolved has-many and has-one associations of foreign keys referring the table
+	hasMany []*hasMany
+	// inferredKeys foreign keys of column names, see Options.InferRelations
+	inferredKeys []*ForeignKey
+	// historyOf table of history table, see Options.HistorySuffix
+	historyOf *Table
+	// goName go struct name of folded upper case table name, see goStructName
+	goName string
 }
 
 type Field struct {
@@ -21,4 +33,32 @@ type Field struct {
 	Comment  string
 	Nullable bool
 	GoType   string
+	Extra    string
+	// AutoIncrement auto increment, serial or identity column
+	AutoIncrement bool
+	// Sequence sequence owned by the column (postgresql)
+	Sequence string
+	// Collation collation of string column (mysql)
+	Collation string
+	// Generated generation expression of generated (computed) column
+	Generated string
+	// CheckName and Check name and expression of the check constraint on the column
+	CheckName string
+	Check     string
+	// EnumValues values of enum column
+	EnumValues []string
+	goEnum     *enum
+	embed      *embedGroup
+	// column original column of the field of embedded struct, tags are of the full column name, e.g. db:"address_street"
+	column *Field
+	// userTags hand-written tags of the field in existing model files
+	userTags map[string]string
+	// configTags extra tags of the column in config file, see ColumnConfig
+	configTags map[string]string
+	// goName folded or truncated go field name, see goFieldName
+	goName string
+	// override go type of options.TypeOverrides of `table.column`
+	override string
+	// unsignedKey `bigint unsigned` primary key or foreign key referencing one, see resolveUnsignedPKs
+	unsignedKey bool
 }
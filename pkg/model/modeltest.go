@@ -0,0 +1,51 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goModelTests tests of models checking table and columns resolved by gorm v2 schema parser match the db,
+// catches mismatch of generated tags and gorm naming, `TestUserSchema` for each model
+func goModelTests(options *Options, tables []*Table) []jen.Code {
+	schemaPkg := "gorm.io/gorm/schema"
+	naming := jen.Dict{}
+	if options.TestSingularTable {
+		naming[jen.Id("SingularTable")] = jen.True()
+	}
+
+	cs := make([]jen.Code, 0, len(tables)+2)
+	cs = append(cs,
+		jen.Comment("testNamingStrategy gorm naming strategy of models, same as gorm config of the app").Line().
+			Var().Id("testNamingStrategy").Op("=").Qual(schemaPkg, "NamingStrategy").Values(naming),
+		jen.Comment("checkSchema check table and columns of model resolved by gorm schema parser").Line().
+			Func().Id("checkSchema").Params(
+			jen.Id("t").Op("*").Qual("testing", "T"),
+			jen.Id("model").Interface(),
+			jen.Id("table").String(),
+			jen.Id("columns").Index().String(),
+		).Block(
+			jen.List(jen.Id("s"), jen.Err()).Op(":=").Qual(schemaPkg, "Parse").Call(jen.Id("model"), jen.Op("&").Qual("sync", "Map").Values(), jen.Id("testNamingStrategy")),
+			jen.If(jen.Err().Op("!=").Nil()).Block(
+				jen.Id("t").Dot("Fatal").Call(jen.Err()),
+			),
+			jen.If(jen.Id("s").Dot("Table").Op("!=").Id("table")).Block(
+				jen.Id("t").Dot("Errorf").Call(jen.Lit("table of %s is %s, want %s"), jen.Id("s").Dot("Name"), jen.Id("s").Dot("Table"), jen.Id("table")),
+			),
+			jen.For(jen.List(jen.Id("_"), jen.Id("column")).Op(":=").Range().Id("columns")).Block(
+				jen.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("s").Dot("FieldsByDBName").Index(jen.Id("column")), jen.Op("!").Id("ok")).Block(
+					jen.Id("t").Dot("Errorf").Call(jen.Lit("column %s of %s not found"), jen.Id("column"), jen.Id("table")),
+				),
+			),
+		),
+	)
+
+	for _, table := range tables {
+		name := goStructName(table)
+		columns := make([]jen.Code, 0, len(table.Fields))
+		for _, f := range table.Fields {
+			columns = append(columns, jen.Lit(f.Field))
+		}
+		cs = append(cs, jen.Func().Id("Test"+name+"Schema").Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+			jen.Id("checkSchema").Call(jen.Id("t"), jen.Op("&").Id(name).Values(), jen.Lit(table.Name), jen.Index().String().Values(columns...)),
+		))
+	}
+	return cs
+}
@@ -1,49 +1,44 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 )
 
 type mysql struct{}
 
-func (t *mysql) dbStruct(options *Options) (tables []*Table, err error) {
+func (t *mysql) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
 	var db *gorm.DB
-	db, err = newDb(options.DbType, options.Dsn)
+	db, err = connect(options)
 	if err != nil {
+		// Error 1049: Unknown database
+		var e *mysqldriver.MySQLError
+		if errors.As(err, &e) && e.Number == 1049 {
+			err = fmt.Errorf("database %q not found", t.databaseName(options.Dsn))
+		}
 		return
 	}
 
+	// database of reused connection is not known by dsn
+	if options.DB == nil {
+		if err = t.checkDatabase(db, options.Dsn); err != nil {
+			return
+		}
+	}
+
 	if options.Verbose {
 		l.Println("mysql dump db struct")
 	}
 
-	if len(options.Filters) > 0 {
-		tables = make([]*Table, 0, 1024)
-		nameSet := make(map[string]bool)
-
-		for _, filter := range options.Filters {
-			var tbs []*Table
-			tbs, err = t.filterTables(db, filter, options.Exclude)
-			if err != nil {
-				return
-			}
-
-			for _, table := range tbs {
-				if _, ok := nameSet[table.Name]; ok {
-					continue
-				}
-				nameSet[table.Name] = true
-				tables = append(tables, table)
-			}
-		}
-	} else {
-		tables, err = t.filterTables(db, nil, options.Exclude)
-	}
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		return t.filterTables(ctx, db, filter, options)
+	})
 
 	if options.Verbose && err == nil {
 		l.Println("dump completed, table count:", len(tables))
@@ -52,8 +47,46 @@ func (t *mysql) dbStruct(options *Options) (tables []*Table, err error) {
 	return
 }
 
-func (t *mysql) filterTables(db *gorm.DB, filter *Filter, exclude []string) (tables []*Table, err error) {
+// checkDatabase check database of dsn exists, dsn without database selects nothing
+func (t *mysql) checkDatabase(db *gorm.DB, dsn string) error {
+	name := t.databaseName(dsn)
+	if name == "" {
+		return fmt.Errorf("dsn %q selects no database", t.redact(dsn))
+	}
+	var count int
+	err := db.Table("information_schema.schemata").Where("schema_name = ?", name).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("database %q not found", name)
+	}
+	return nil
+}
+
+// databaseName database name of dsn, empty if dsn is invalid or selects no database
+func (t *mysql) databaseName(dsn string) string {
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return ""
+	}
+	return cfg.DBName
+}
+
+// redact dsn with password masked, shown in errors
+func (t *mysql) redact(dsn string) string {
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil || cfg.Passwd == "" {
+		return dsn
+	}
+	cfg.Passwd = "xxxxx"
+	return cfg.FormatDSN()
+}
+
+// filterTables tables of filter, columns and foreign keys are queried in batches, ddl by workers
+func (t *mysql) filterTables(ctx context.Context, db *gorm.DB, filter *Filter, options *Options) (tables []*Table, err error) {
 	type mysqlTable struct {
+		Schema  string `gorm:"column:table_schema"`
 		Name    string `gorm:"column:table_name"`
 		Comment string `gorm:"column:table_comment"`
 	}
@@ -61,7 +94,7 @@ func (t *mysql) filterTables(db *gorm.DB, filter *Filter, exclude []string) (tab
 	var dbTables []*mysqlTable
 
 	tdb := db.Table("information_schema.tables").
-		Select("table_name, table_comment").
+		Select("table_schema, table_name, table_comment").
 		Where("table_schema = database()")
 
 	if filter != nil {
@@ -69,8 +102,8 @@ func (t *mysql) filterTables(db *gorm.DB, filter *Filter, exclude []string) (tab
 		tdb = tdb.Where("table_name like ?", filter.TableNamePattern)
 	}
 
-	if len(exclude) > 0 {
-		tdb.Where("table_name not in(?)", exclude)
+	if len(options.Exclude) > 0 {
+		tdb = tdb.Where("table_name not in(?)", options.Exclude)
 	}
 
 	err = tdb.Find(&dbTables).Error
@@ -79,29 +112,48 @@ func (t *mysql) filterTables(db *gorm.DB, filter *Filter, exclude []string) (tab
 	}
 
 	tables = make([]*Table, 0, len(dbTables))
-
+	names := make([]string, 0, len(dbTables))
 	for _, it := range dbTables {
 		tb := &Table{
+			Schema:  it.Schema,
 			Name:    it.Name,
 			Comment: it.Comment,
 		}
-
-		tb.Ddl = t.tableDdl(db, it.Name)
-		tb.Fields, err = t.tableFields(db, it.Name)
-		if err != nil {
-			return
-		}
-
 		if filter != nil {
 			tb.Prefix = filter.TablePrefix
 		}
-
 		tables = append(tables, tb)
+		names = append(names, it.Name)
 	}
 
+	fields := make(map[string][]*Field, len(names))
+	fks := make(map[string][]*ForeignKey, len(names))
+	for _, chunk := range chunkNames(names, mysqlBatchSize) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if err = t.tableFields(db, chunk, fields); err != nil {
+			return
+		}
+		if err = t.foreignKeys(db, chunk, fks); err != nil {
+			return
+		}
+	}
+
+	err = introspectTables(ctx, options, tables, func(tb *Table) error {
+		tb.Ddl = t.tableDdl(db, tb.Name)
+		tb.Fields = fields[tb.Name]
+		parseMysqlChecks(tb.Ddl, tb.Fields)
+		parseMysqlGenerated(tb.Ddl, tb.Fields)
+		tb.ForeignKeys = singleColumnForeignKeys(fks[tb.Name])
+		return nil
+	})
 	return
 }
 
+// mysqlBatchSize tables of a batch query of columns or foreign keys
+const mysqlBatchSize = 500
+
 func (t *mysql) tableDdl(db *gorm.DB, name string) (ddl string) {
 	row := db.Raw(fmt.Sprint("show create table ", name)).Row()
 	if db.Error != nil {
@@ -111,8 +163,36 @@ func (t *mysql) tableDdl(db *gorm.DB, name string) (ddl string) {
 	return
 }
 
-func (t *mysql) tableFields(db *gorm.DB, name string) (fields []*Field, err error) {
+// foreignKeys add foreign keys of tables to fks keyed by table name
+func (t *mysql) foreignKeys(db *gorm.DB, names []string, fks map[string][]*ForeignKey) error {
+	type mysqlForeignKey struct {
+		Table string `gorm:"column:table_name"`
+		ForeignKey
+	}
+
+	var dbFks []*mysqlForeignKey
+	err := db.Raw(`select k.table_name as table_name, k.constraint_name as name, k.column_name as `+"`column`"+`,
+       k.referenced_table_name as ref_table, k.referenced_column_name as ref_column,
+       r.delete_rule as on_delete, r.update_rule as on_update
+from information_schema.key_column_usage k
+         join information_schema.referential_constraints r
+              on r.constraint_schema = k.constraint_schema and r.constraint_name = k.constraint_name
+where k.table_schema = database() and k.table_name in (?) and k.referenced_table_name is not null
+order by k.table_name, k.constraint_name, k.ordinal_position`, names).Scan(&dbFks).Error
+	if err != nil {
+		return err
+	}
+	for _, it := range dbFks {
+		fk := it.ForeignKey
+		fks[it.Table] = append(fks[it.Table], &fk)
+	}
+	return nil
+}
+
+// tableFields add columns of tables to fields keyed by table name
+func (t *mysql) tableFields(db *gorm.DB, names []string, fields map[string][]*Field) (err error) {
 	type mysqlField struct {
+		TableName     string `gorm:"column:table_name"`
 		ColumnName    string `gorm:"column:column_name"`
 		ColumnDefault string `gorm:"column:column_default"`
 		IsNullable    string `gorm:"column:is_nullable"`
@@ -121,55 +201,62 @@ func (t *mysql) tableFields(db *gorm.DB, name string) (fields []*Field, err erro
 		ColumnKey     string `gorm:"column:column_key"`
 		Extra         string `gorm:"column:extra"`
 		ColumnComment string `gorm:"column:column_comment"`
+		Collation     string `gorm:"column:collation_name"`
 	}
 
 	var dbFields []*mysqlField
 
 	fdb := db.Table("information_schema.columns").
-		Select("column_name, column_default, is_nullable, data_type, column_type, column_key, extra, column_comment").
-		Where("table_schema=database() and table_name=?", name)
+		Select("table_name, column_name, column_default, is_nullable, data_type, column_type, column_key, extra, column_comment, ifnull(collation_name, '') as collation_name").
+		Where("table_schema=database() and table_name in (?)", names).
+		Order("table_name, ordinal_position")
 	err = fdb.Find(&dbFields).Error
 	if err != nil {
 		return
 	}
 
-	fields = make([]*Field, 0, len(dbFields))
 	for _, it := range dbFields {
 		field := &Field{
-			Field:   it.ColumnName,
-			Type:    strings.ToLower(it.ColumnType),
-			Null:    strings.ToUpper(it.IsNullable),
-			Key:     it.ColumnKey,
-			Default: it.ColumnDefault,
-			Comment: it.ColumnComment,
+			Field:     it.ColumnName,
+			Type:      strings.ToLower(it.ColumnType),
+			Null:      strings.ToUpper(it.IsNullable),
+			Key:       it.ColumnKey,
+			Default:   it.ColumnDefault,
+			Comment:   it.ColumnComment,
+			Extra:     it.Extra,
+			Collation: it.Collation,
 		}
 
 		if field.Null == "YES" {
 			field.Nullable = true
 		}
+		if strings.Contains(strings.ToLower(field.Extra), "auto_increment") {
+			field.AutoIncrement = true
+		}
 
-		field.GoType = t.getGoType(field.Type)
+		field.GoType, _ = t.getGoType(field.Type)
+		field.EnumValues = parseEnumValues(field.Type)
 
-		fields = append(fields, field)
+		fields[it.TableName] = append(fields[it.TableName], field)
 	}
 
 	return
 }
 
-func (t *mysql) getGoType(dbType string) string {
+func (t *mysql) getGoType(dbType string) (string, bool) {
 	// 精确匹配
 	if v, ok := typeMysqlDic[dbType]; ok {
-		return v
+		return v, true
 	}
 
 	// 正则匹配
 	for _, v := range typeMysqlMatch {
 		if ok, _ := regexp.MatchString(v[0], dbType); ok {
-			return v[1]
+			return v[1], true
 		}
 	}
 
-	panic(fmt.Sprintf("unkonow type: %s", dbType))
+	return "", false
 }
 
 // typeMysqlDic matching type
@@ -0,0 +1,68 @@
+package model
+
+// sqlNullTypes database/sql nullable type of go type
+var sqlNullTypes = map[string]string{
+	"string":    "NullString",
+	"int8":      "NullInt16",
+	"uint8":     "NullByte",
+	"int16":     "NullInt16",
+	"uint16":    "NullInt32",
+	"int":       "NullInt64",
+	"int32":     "NullInt32",
+	"uint":      "NullInt64",
+	"uint32":    "NullInt64",
+	"int64":     "NullInt64",
+	"float32":   "NullFloat64",
+	"float64":   "NullFloat64",
+	"bool":      "NullBool",
+	"time.Time": "NullTime",
+}
+
+// customNullTypes nullable type name of go type for custom package, compatible with guregu/null
+var customNullTypes = map[string]string{
+	"string":    "String",
+	"int":       "Int",
+	"uint":      "Int",
+	"int8":      "Int",
+	"uint8":     "Int",
+	"int16":     "Int",
+	"uint16":    "Int",
+	"int32":     "Int",
+	"uint32":    "Int",
+	"int64":     "Int",
+	"float32":   "Float",
+	"float64":   "Float",
+	"bool":      "Bool",
+	"time.Time": "Time",
+}
+
+// nullableType returns qualified nullable type of go type, name is empty if a pointer should be used
+func nullableType(options *Options, goType string) (path, name string) {
+	switch options.NullableStrategy {
+	case NullableSql:
+		return "database/sql", sqlNullTypes[goType]
+	case NullableCustom, NullableGuregu:
+		pkg := nullablePackage(options)
+		if pkg == "" {
+			return "", ""
+		}
+		if v, ok := options.NullableTypes[goType]; ok {
+			return pkg, v
+		}
+		return pkg, customNullTypes[goType]
+	}
+	return "", ""
+}
+
+// nullablePackage package of custom nullable types, empty unless custom or guregu strategy
+func nullablePackage(options *Options) string {
+	switch {
+	case options.NullableStrategy != NullableCustom && options.NullableStrategy != NullableGuregu:
+		return ""
+	case options.NullablePackage != "":
+		return options.NullablePackage
+	case options.NullableStrategy == NullableGuregu:
+		return gureguNullPackage
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownType = errors.New("unknown column type")
+
+// typeOverride go type of options.TypeOverrides of `table.column`, options.BinaryCollationBytes, or options.TypeOverrides
+// keyed by db type, db type without length or precision, e.g. decimal of decimal(10,2), then go type
+func typeOverride(options *Options, field *Field) (string, bool) {
+	if field.override != "" {
+		return field.override, true
+	}
+	if options.BinaryCollationBytes && field.GoType == "string" && binaryCollation(field.Collation) {
+		return "[]byte", true
+	}
+	if len(options.TypeOverrides) == 0 {
+		return "", false
+	}
+
+	keys := []string{field.Type}
+	if i := strings.Index(field.Type, "("); i > 0 {
+		keys = append(keys, strings.TrimSpace(field.Type[:i]))
+	}
+	keys = append(keys, field.GoType)
+	for _, key := range keys {
+		if v, ok := options.TypeOverrides[key]; ok && v != "" {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// binaryCollation returns true if collation compares bytes, e.g. utf8mb4_bin
+func binaryCollation(collation string) bool {
+	return collation == "binary" || strings.HasSuffix(collation, "_bin")
+}
+
+// resolveTypeOverrides assign options.TypeOverrides of `table.column` to fields
+func resolveTypeOverrides(options *Options, tables []*Table) {
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			f.override = options.TypeOverrides[fmt.Sprint(table.Name, ".", f.Field)]
+		}
+	}
+}
+
+// resolveUnknownTypes columns of unknown db type not in options.TypeOverrides fall back to interface{}, warned
+// if options.Verbose. Returns ErrUnknownType listing them if options.StrictTypes
+func resolveUnknownTypes(options *Options, tables []*Table) error {
+	columns := make([]string, 0)
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			if f.GoType != "" {
+				continue
+			}
+			if _, ok := typeOverride(options, f); ok {
+				continue
+			}
+			column := fmt.Sprintf("%s.%s(%s)", table.Name, f.Field, f.Type)
+			if options.StrictTypes {
+				columns = append(columns, column)
+				continue
+			}
+			if options.Verbose {
+				l.Printf("unknown type of column %s, fallback to interface{}, map it by TypeOverrides", column)
+			}
+			f.GoType = "interface{}"
+		}
+	}
+
+	if len(columns) > 0 {
+		return fmt.Errorf("%w, map them by TypeOverrides: %s", ErrUnknownType, strings.Join(columns, ", "))
+	}
+	return nil
+}
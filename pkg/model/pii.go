@@ -0,0 +1,29 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// piiTag tag key of pii columns
+func piiTag(options *Options) string {
+	if options.PIITag != "" {
+		return options.PIITag
+	}
+	return "pii"
+}
+
+// goPIIFieldsMethod `PIIFields() []string` returns pii column names of table, nil if table has none
+func goPIIFieldsMethod(options *Options, name string, table *Table) jen.Code {
+	columns := make([]jen.Code, 0, 2)
+	for _, f := range table.Fields {
+		if columnIn(options.PIIColumns, table, f) {
+			columns = append(columns, jen.Lit(f.Field))
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	return jen.Commentf("PIIFields returns pii columns of %v", table.Name).Line().
+		Func().Params(jen.Id(receiverName(options, name, "")).Add(receiverType(options, name))).Id("PIIFields").Params().Index().String().Block(
+		jen.Return(jen.Index().String().Values(columns...)),
+	)
+}
@@ -1,7 +1,424 @@
 package model
 
-type postgresql struct{}
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
-func (t *postgresql) dbStruct(options *Options) ([]*Table, error) {
-	panic("todo")
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// postgresql postgresql, or amazon redshift which is postgresql wire compatible with a different catalog
+type postgresql struct {
+	redshift bool
+	// version server_version_num, e.g. 120004
+	version int
+}
+
+func (t *postgresql) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
+	var db *gorm.DB
+	db, err = connect(options)
+	if err != nil {
+		// 3D000: invalid_catalog_name
+		var e *pq.Error
+		if errors.As(err, &e) && e.Code == "3D000" {
+			err = fmt.Errorf("database %q not found", t.databaseName(options.Dsn))
+		}
+		return
+	}
+
+	if err = t.checkDatabase(db); err != nil {
+		return
+	}
+	if !t.redshift {
+		if err = db.Raw("select current_setting('server_version_num')::int as version").Row().Scan(&t.version); err != nil {
+			return
+		}
+	}
+
+	if options.Verbose {
+		l.Println(options.DbType, "dump db struct")
+	}
+
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		return t.filterTables(ctx, db, filter, options)
+	})
+	if err == nil && options.GenSequenceConstants && !t.redshift {
+		options.Sequences, err = t.sequences(db)
+	}
+
+	if options.Verbose && err == nil {
+		l.Println("dump completed, table count:", len(tables))
+	}
+
+	return
+}
+
+// checkDatabase check schema of search_path exists, current_schema() is null otherwise
+func (t *postgresql) checkDatabase(db *gorm.DB) error {
+	var current struct {
+		Database   string `gorm:"column:database"`
+		Schema     string `gorm:"column:schema"`
+		SearchPath string `gorm:"column:search_path"`
+	}
+	err := db.Raw("select current_database() as database, coalesce(current_schema(), '') as schema, current_setting('search_path') as search_path").
+		Scan(&current).Error
+	if err != nil {
+		return err
+	}
+	if current.Schema == "" {
+		return fmt.Errorf("schema %q not found in database %q", current.SearchPath, current.Database)
+	}
+	return nil
+}
+
+// databaseName database name of url or key=value dsn
+func (t *postgresql) databaseName(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimPrefix(u.Path, "/")
+	}
+
+	for _, kv := range strings.Fields(dsn) {
+		if strings.HasPrefix(kv, "dbname=") {
+			return strings.Trim(strings.TrimPrefix(kv, "dbname="), "'")
+		}
+	}
+	return ""
+}
+
+// filterTables tables of filter, columns and foreign keys are queried by workers
+func (t *postgresql) filterTables(ctx context.Context, db *gorm.DB, filter *Filter, options *Options) (tables []*Table, err error) {
+	type postgresqlTable struct {
+		Schema  string `gorm:"column:table_schema"`
+		Name    string `gorm:"column:table_name"`
+		Comment string `gorm:"column:table_comment"`
+	}
+
+	var dbTables []*postgresqlTable
+
+	tdb := db.Table("information_schema.tables").
+		Select("table_schema, table_name, coalesce(obj_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, 'pg_class'), '') as table_comment").
+		Where("table_schema = current_schema() and table_type = 'BASE TABLE'")
+
+	if filter != nil {
+		l.Println("filter table_name like", filter.TableNamePattern)
+		tdb = tdb.Where("table_name like ?", filter.TableNamePattern)
+	}
+
+	if len(options.Exclude) > 0 {
+		tdb = tdb.Where("table_name not in(?)", options.Exclude)
+	}
+
+	err = tdb.Order("table_name").Find(&dbTables).Error
+	if err != nil {
+		return
+	}
+
+	tables = make([]*Table, 0, len(dbTables))
+	for _, it := range dbTables {
+		tb := &Table{
+			Schema:  it.Schema,
+			Name:    it.Name,
+			Comment: it.Comment,
+		}
+		if filter != nil {
+			tb.Prefix = filter.TablePrefix
+		}
+		tables = append(tables, tb)
+	}
+
+	err = introspectTables(ctx, options, tables, func(tb *Table) (err error) {
+		if t.redshift {
+			// foreign keys of redshift are informational only, not introspected
+			return t.redshiftFields(db, tb)
+		}
+		if tb.Fields, err = t.tableFields(db, tb.Name); err != nil {
+			return
+		}
+		if tb.ForeignKeys, err = t.foreignKeys(db, tb.Name); err != nil {
+			return
+		}
+		tb.Ddl = t.tableDdl(tb)
+		return
+	})
+	return
+}
+
+// sequences standalone sequences of current schema, sequences of serial (auto dependency) and identity
+// (internal dependency) columns are owned by columns
+func (t *postgresql) sequences(db *gorm.DB) (names []string, err error) {
+	var sequences []*struct {
+		Name string `gorm:"column:name"`
+	}
+	err = db.Raw(`select c.relname as name
+from pg_class c
+  join pg_namespace n on n.oid = c.relnamespace
+where c.relkind = 'S' and n.nspname = current_schema()
+  and not exists (select 1 from pg_depend d
+    where d.classid = 'pg_class'::regclass and d.objid = c.oid and d.deptype in ('a', 'i'))
+order by c.relname`).Scan(&sequences).Error
+	if err != nil {
+		return
+	}
+	names = make([]string, 0, len(sequences))
+	for _, it := range sequences {
+		names = append(names, it.Name)
+	}
+	return
+}
+
+// tableDdl reconstruct create table statement from fields, postgresql has no `show create table`, serial columns
+// are serial types as the sequence of nextval default may not exist
+func (t *postgresql) tableDdl(table *Table) string {
+	lines := make([]string, 0, len(table.Fields)+len(table.ForeignKeys)+1)
+	pks := make([]string, 0, 1)
+	for _, f := range table.Fields {
+		typ, def := f.Type, f.Default
+		if f.AutoIncrement && !t.redshift {
+			if f.Extra == "identity" {
+				typ, def = typ+" generated by default as identity", ""
+			} else if serial, ok := postgresqlSerials[typ]; ok {
+				typ, def = serial, ""
+			}
+		}
+		line := fmt.Sprintf("  %s %s", sqlIdent(DbTypePostgreSQL, f.Field), typ)
+		if !f.Nullable {
+			line += " not null"
+		}
+		if def != "" {
+			line += " default " + def
+		}
+		lines = append(lines, line)
+		if f.Key == "PRI" {
+			pks = append(pks, sqlIdent(DbTypePostgreSQL, f.Field))
+		}
+	}
+	if len(pks) > 0 {
+		lines = append(lines, fmt.Sprintf("  primary key (%s)", strings.Join(pks, ", ")))
+	}
+	for _, fk := range table.ForeignKeys {
+		line := fmt.Sprintf("  constraint %s foreign key (%s) references %s (%s)", sqlIdent(DbTypePostgreSQL, fk.Name),
+			sqlIdent(DbTypePostgreSQL, fk.Column), sqlIdent(DbTypePostgreSQL, fk.RefTable), sqlIdent(DbTypePostgreSQL, fk.RefColumn))
+		if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+			line += " on delete " + strings.ToLower(fk.OnDelete)
+		}
+		if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+			line += " on update " + strings.ToLower(fk.OnUpdate)
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf("create table %s (\n%s\n);", sqlIdent(DbTypePostgreSQL, table.Name), strings.Join(lines, ",\n"))
+}
+
+// postgresqlSerials serial types of integer types
+var postgresqlSerials = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"bigint":   "bigserial",
+}
+
+// foreignKeys single column foreign keys of table
+func (t *postgresql) foreignKeys(db *gorm.DB, name string) (fks []*ForeignKey, err error) {
+	err = db.Raw(`select r.conname as name, a.attname as column, fc.relname as ref_table, fa.attname as ref_column,
+       r.confdeltype::text as on_delete, r.confupdtype::text as on_update
+from pg_constraint r
+         join pg_class c on c.oid = r.conrelid
+         join pg_namespace n on n.oid = c.relnamespace
+         join pg_class fc on fc.oid = r.confrelid
+         join pg_attribute a on a.attrelid = r.conrelid and a.attnum = r.conkey[1]
+         join pg_attribute fa on fa.attrelid = r.confrelid and fa.attnum = r.confkey[1]
+where r.contype = 'f' and n.nspname = current_schema() and c.relname = ? and array_length(r.conkey, 1) = 1
+order by r.conname`, name).Scan(&fks).Error
+	for _, fk := range fks {
+		fk.OnDelete = postgresqlActions[fk.OnDelete]
+		fk.OnUpdate = postgresqlActions[fk.OnUpdate]
+	}
+	return
+}
+
+// postgresqlActions referential actions of pg_constraint confdeltype and confupdtype
+var postgresqlActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+func (t *postgresql) tableFields(db *gorm.DB, name string) (fields []*Field, err error) {
+	type postgresqlField struct {
+		ColumnName    string `gorm:"column:column_name"`
+		ColumnDefault string `gorm:"column:column_default"`
+		Nullable      bool   `gorm:"column:nullable"`
+		ColumnType    string `gorm:"column:column_type"`
+		PrimaryKey    bool   `gorm:"column:primary_key"`
+		Identity      string `gorm:"column:identity"`
+		Sequence      string `gorm:"column:sequence"`
+		ColumnComment string `gorm:"column:column_comment"`
+		CheckName     string `gorm:"column:check_name"`
+		CheckDef      string `gorm:"column:check_def"`
+		Generated     string `gorm:"column:generated"`
+		EnumValues    string `gorm:"column:enum_values"`
+	}
+
+	var dbFields []*postgresqlField
+
+	// attgenerated since postgresql 12
+	generated := "''"
+	if t.version >= 120000 {
+		generated = "a.attgenerated::text"
+	}
+
+	fdb := db.Raw(`select a.attname as column_name,
+       coalesce(pg_get_expr(d.adbin, d.adrelid), '') as column_default,
+       not a.attnotnull as nullable,
+       format_type(a.atttypid, a.atttypmod) as column_type,
+       exists(select 1 from pg_index i where i.indrelid = a.attrelid and i.indisprimary and a.attnum = any(i.indkey)) as primary_key,
+       a.attidentity::text as identity,
+       coalesce(pg_get_serial_sequence(quote_ident(n.nspname) || '.' || quote_ident(c.relname), a.attname), '') as sequence,
+       coalesce(col_description(a.attrelid, a.attnum), '') as column_comment,
+       coalesce(ck.conname, '') as check_name,
+       coalesce(ck.def, '') as check_def,
+       `+generated+` as generated,
+       coalesce((select json_agg(e.enumlabel order by e.enumsortorder)::text from pg_enum e where e.enumtypid = a.atttypid), '') as enum_values
+from pg_attribute a
+         join pg_class c on c.oid = a.attrelid
+         join pg_namespace n on n.oid = c.relnamespace
+         left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum
+         left join lateral (select r.conname, pg_get_constraintdef(r.oid) as def
+                            from pg_constraint r
+                            where r.conrelid = a.attrelid and r.contype = 'c' and r.conkey = array[a.attnum]
+                            order by r.conname
+                            limit 1) ck on true
+where n.nspname = current_schema() and c.relname = ? and a.attnum > 0 and not a.attisdropped
+order by a.attnum`, name)
+	err = fdb.Scan(&dbFields).Error
+	if err != nil {
+		return
+	}
+
+	fields = make([]*Field, 0, len(dbFields))
+	for _, it := range dbFields {
+		field := &Field{
+			Field:    it.ColumnName,
+			Type:     strings.ToLower(it.ColumnType),
+			Null:     "NO",
+			Default:  it.ColumnDefault,
+			Comment:  it.ColumnComment,
+			Nullable: it.Nullable,
+			Sequence: it.Sequence,
+		}
+
+		// default of stored generated column is the generation expression
+		if it.Generated == "s" {
+			field.Generated = field.Default
+			field.Default = ""
+		}
+
+		if it.CheckName != "" {
+			field.CheckName = it.CheckName
+			field.Check = postgresqlCheckExpr(it.CheckDef)
+		}
+
+		if field.Nullable {
+			field.Null = "YES"
+		}
+		if it.PrimaryKey {
+			field.Key = "PRI"
+		}
+
+		// serial column defaults to nextval of its sequence, identity column is generated always/by default
+		if it.Identity != "" || strings.HasPrefix(field.Default, "nextval(") {
+			field.AutoIncrement = true
+		}
+		if it.Identity != "" {
+			field.Extra = "identity"
+		}
+
+		// labels of enum type, e.g. ["sad","happy"]
+		if it.EnumValues != "" {
+			if err = json.Unmarshal([]byte(it.EnumValues), &field.EnumValues); err != nil {
+				return
+			}
+			field.GoType = "string"
+		} else {
+			field.GoType, _ = t.getGoType(field.Type)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return
+}
+
+func (t *postgresql) getGoType(dbType string) (string, bool) {
+	if t.redshift {
+		if v, ok := typeRedshiftDic[dbType]; ok {
+			return v, true
+		}
+	}
+
+	// 精确匹配
+	if v, ok := typePostgresqlDic[dbType]; ok {
+		return v, true
+	}
+
+	// 正则匹配
+	for _, v := range typePostgresqlMatch {
+		if ok, _ := regexp.MatchString(v[0], dbType); ok {
+			return v[1], true
+		}
+	}
+
+	return "", false
+}
+
+// typePostgresqlDic matching type
+var typePostgresqlDic = map[string]string{
+	"smallint":                    "int16",
+	"integer":                     "int32",
+	"bigint":                      "int64",
+	"real":                        "float32",
+	"double precision":            "float64",
+	"numeric":                     "float64",
+	"money":                       "string",
+	"boolean":                     "bool",
+	"text":                        "string",
+	"character varying":           "string",
+	"character":                   "string",
+	"citext":                      "string",
+	"uuid":                        "string",
+	"json":                        "string",
+	"jsonb":                       "string",
+	"xml":                         "string",
+	"inet":                        "string",
+	"cidr":                        "string",
+	"macaddr":                     "string",
+	"interval":                    "string",
+	"bytea":                       "[]byte",
+	"date":                        "time.Time",
+	"timestamp without time zone": "time.Time",
+	"timestamp with time zone":    "time.Time",
+	"time without time zone":      "time.Time",
+	"time with time zone":         "time.Time",
+}
+
+// typePostgresqlMatch regx match types
+var typePostgresqlMatch = [][]string{
+	{`\[\]$`, "string"},
+	{`^(character varying)[(]\d+[)]`, "string"},
+	{`^(character)[(]\d+[)]`, "string"},
+	{`^(bit)( varying)?[(]\d+[)]`, "string"},
+	{`^(numeric)[(]\d+(,\d+)?[)]`, "float64"},
+	{`^(timestamp)[(]\d+[)] with(out)? time zone`, "time.Time"},
+	{`^(time)[(]\d+[)] with(out)? time zone`, "time.Time"},
 }
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// redshiftFields fields of redshift table, distribution and sort keys are added to table comment and ddl,
+// redshift has no identity column attribute nor serial sequence, identity is told by the default expression
+func (t *postgresql) redshiftFields(db *gorm.DB, table *Table) (err error) {
+	type redshiftField struct {
+		ColumnName    string `gorm:"column:column_name"`
+		ColumnDefault string `gorm:"column:column_default"`
+		Nullable      bool   `gorm:"column:nullable"`
+		ColumnType    string `gorm:"column:column_type"`
+		PrimaryKey    bool   `gorm:"column:primary_key"`
+		DistKey       bool   `gorm:"column:dist_key"`
+		SortKey       int    `gorm:"column:sort_key"`
+		ColumnComment string `gorm:"column:column_comment"`
+	}
+
+	var dbFields []*redshiftField
+
+	err = db.Raw(`select a.attname as column_name,
+       coalesce(pg_get_expr(d.adbin, d.adrelid), '') as column_default,
+       not a.attnotnull as nullable,
+       format_type(a.atttypid, a.atttypmod) as column_type,
+       exists(select 1 from pg_constraint r where r.conrelid = a.attrelid and r.contype = 'p' and a.attnum = any(r.conkey)) as primary_key,
+       a.attisdistkey as dist_key,
+       a.attsortkeyord as sort_key,
+       coalesce(col_description(a.attrelid, a.attnum), '') as column_comment
+from pg_attribute a
+         join pg_class c on c.oid = a.attrelid
+         join pg_namespace n on n.oid = c.relnamespace
+         left join pg_attrdef d on d.adrelid = a.attrelid and d.adnum = a.attnum
+where n.nspname = current_schema() and c.relname = ? and a.attnum > 0 and not a.attisdropped
+order by a.attnum`, table.Name).Scan(&dbFields).Error
+	if err != nil {
+		return
+	}
+
+	var (
+		distKey  string
+		sortKeys = make([]*redshiftField, 0, 2)
+	)
+	table.Fields = make([]*Field, 0, len(dbFields))
+	for _, it := range dbFields {
+		field := &Field{
+			Field:    it.ColumnName,
+			Type:     strings.ToLower(it.ColumnType),
+			Null:     "NO",
+			Default:  it.ColumnDefault,
+			Comment:  it.ColumnComment,
+			Nullable: it.Nullable,
+		}
+		if field.Nullable {
+			field.Null = "YES"
+		}
+		if it.PrimaryKey {
+			field.Key = "PRI"
+		}
+		if strings.HasPrefix(field.Default, `"identity"(`) {
+			field.AutoIncrement = true
+			field.Extra = "identity"
+		}
+		if it.DistKey {
+			distKey = it.ColumnName
+		}
+		// negative order is interleaved sort key
+		if it.SortKey != 0 {
+			sortKeys = append(sortKeys, it)
+		}
+
+		field.GoType, _ = t.getGoType(field.Type)
+		table.Fields = append(table.Fields, field)
+	}
+
+	sort.Slice(sortKeys, func(i, j int) bool {
+		return abs(sortKeys[i].SortKey) < abs(sortKeys[j].SortKey)
+	})
+	sortKeyNames := make([]string, 0, len(sortKeys))
+	for _, it := range sortKeys {
+		sortKeyNames = append(sortKeyNames, it.ColumnName)
+	}
+
+	table.Ddl = t.tableDdl(table)
+	keys := make([]string, 0, 2)
+	if distKey != "" {
+		keys = append(keys, fmt.Sprintf("distkey: %s", distKey))
+		table.Ddl = strings.TrimSuffix(table.Ddl, ";") + fmt.Sprintf("\ndistkey(%s);", distKey)
+	}
+	if len(sortKeyNames) > 0 {
+		keys = append(keys, fmt.Sprintf("sortkey: %s", strings.Join(sortKeyNames, ", ")))
+		table.Ddl = strings.TrimSuffix(table.Ddl, ";") + fmt.Sprintf("\nsortkey(%s);", strings.Join(sortKeyNames, ", "))
+	}
+	if len(keys) > 0 {
+		table.Comment = strings.TrimSpace(fmt.Sprintf("%s (%s)", table.Comment, strings.Join(keys, "; ")))
+	}
+	return
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// typeRedshiftDic matching types only redshift has, other types are the same as postgresql
+var typeRedshiftDic = map[string]string{
+	"super":          "string",
+	"hllsketch":      "string",
+	"geometry":       "[]byte",
+	"geography":      "[]byte",
+	"varbyte":        "[]byte",
+	"binary varying": "[]byte",
+	"timetz":         "time.Time",
+	"timestamptz":    "time.Time",
+}
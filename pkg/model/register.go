@@ -0,0 +1,36 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goInit `init()` registers all models, appends to package level `Models` or calls options.InitRegisterFunc,
+// referenced models of foreign keys are registered first, so the list is safe for auto migration
+func goInit(options *Options, tables []*Table) *jen.Statement {
+	sorted, cycles := dependencyOrder(tables)
+	for _, w := range cycleWarnings(cycles) {
+		l.Println(w)
+	}
+
+	models := make([]jen.Code, 0, len(tables))
+	for _, table := range sorted {
+		models = append(models, jen.Op("&").Id(goStructName(table)).Values())
+	}
+
+	if options.InitRegisterFunc != "" {
+		path, name := qualifiedType(options.InitRegisterFunc)
+		calls := make([]jen.Code, 0, len(models))
+		for _, m := range models {
+			calls = append(calls, jen.Qual(path, name).Call(m))
+		}
+		return jen.Func().Id("init").Params().Block(calls...)
+	}
+
+	return jen.Comment("Models all generated models, registered by init").Line().
+		Var().Id("Models").Index().Interface().Line().Line().
+		Func().Id("init").Params().Block(
+		jen.Id("Models").Op("=").Append(append([]jen.Code{jen.Id("Models")}, models...)...),
+	).Line().Line().
+		Comment("AllModels returns all generated models, referenced models of foreign keys come first, e.g. for AutoMigrate").Line().
+		Func().Id("AllModels").Params().Index().Interface().Block(
+		jen.Return(jen.Append(jen.Index().Interface().Values(), jen.Id("Models").Op("..."))),
+	)
+}
@@ -0,0 +1,230 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// ForeignKey single column foreign key of table
+type ForeignKey struct {
+	Name      string `gorm:"column:name"`
+	Column    string `gorm:"column:column"`
+	RefTable  string `gorm:"column:ref_table"`
+	RefColumn string `gorm:"column:ref_column"`
+	// OnDelete and OnUpdate referential actions, e.g. CASCADE, SET NULL
+	OnDelete string `gorm:"column:on_delete"`
+	OnUpdate string `gorm:"column:on_update"`
+
+	// resolved association of options.GenRelations
+	field    *Field
+	refTable *Table
+	goName   string
+	pointer  bool
+}
+
+// constraint gorm constraint setting of referential actions, default NO ACTION is omitted
+func (fk *ForeignKey) constraint() string {
+	actions := make([]string, 0, 2)
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+		actions = append(actions, "OnDelete:"+fk.OnDelete)
+	}
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+		actions = append(actions, "OnUpdate:"+fk.OnUpdate)
+	}
+	return strings.Join(actions, ",")
+}
+
+// singleColumnForeignKeys drop composite foreign keys, rows of a key are adjacent
+func singleColumnForeignKeys(rows []*ForeignKey) []*ForeignKey {
+	fks := make([]*ForeignKey, 0, len(rows))
+	for i, fk := range rows {
+		if i > 0 && rows[i-1].Name == fk.Name || i+1 < len(rows) && rows[i+1].Name == fk.Name {
+			continue
+		}
+		fks = append(fks, fk)
+	}
+	return fks
+}
+
+// resolveRelations resolve belongs-to association of foreign keys referring generated tables
+// and many2many association of pure pivot tables,
+// association of nullable column is pointer, so is association in a cycle of not null columns
+func resolveRelations(options *Options, tables []*Table) {
+	byName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+		table.many2many = nil
+		table.hasMany = nil
+		for _, fk := range table.ForeignKeys {
+			fk.refTable = nil
+		}
+	}
+	resolveInferredKeys(options, tables, byName)
+	if !options.GenRelations {
+		return
+	}
+
+	for _, table := range tables {
+		names := make(map[string]bool, len(table.Fields))
+		for _, f := range table.Fields {
+			names[goFieldName(f)] = true
+		}
+
+		base := baseField(options, table)
+		for _, fk := range table.associationKeys() {
+			ref, ok := byName[fk.RefTable]
+			if !ok {
+				continue
+			}
+			var field *Field
+			for _, f := range table.Fields {
+				if f.Field == fk.Column && f != base && f.embed == nil {
+					field = f
+				}
+			}
+			if field == nil {
+				continue
+			}
+
+			fk.field = field
+			fk.refTable = ref
+			fk.goName = TitleCase(strings.TrimSuffix(fk.Column, "_id"))
+			if fk.goName == TitleCase(fk.Column) || names[fk.goName] {
+				fk.goName = goStructName(ref)
+			}
+			for names[fk.goName] {
+				fk.goName += "Ref"
+			}
+			names[fk.goName] = true
+		}
+	}
+
+	resolveManyToMany(tables)
+
+	for _, table := range tables {
+		for _, fk := range table.associationKeys() {
+			if fk.refTable != nil {
+				fk.pointer = fk.field.Nullable || reachable(fk.refTable, table, make(map[*Table]bool))
+			}
+		}
+	}
+
+	if options.GenHasMany {
+		resolveHasMany(tables)
+	}
+}
+
+// reachable returns true if to is reachable from table by associations of not null columns
+func reachable(table, to *Table, visited map[*Table]bool) bool {
+	if table == to {
+		return true
+	}
+	visited[table] = true
+	for _, fk := range table.associationKeys() {
+		if fk.refTable == nil || fk.field.Nullable || visited[fk.refTable] {
+			continue
+		}
+		if reachable(fk.refTable, to, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// refFieldName go field name of referenced column
+func refFieldName(fk *ForeignKey) string {
+	for _, f := range fk.refTable.Fields {
+		if f.Field == fk.RefColumn {
+			return goFieldName(f)
+		}
+	}
+	return TitleCase(fk.RefColumn)
+}
+
+// goRelationFields belongs-to association fields of table
+func goRelationFields(options *Options, table *Table) []jen.Code {
+	keys := table.associationKeys()
+	cs := make([]jen.Code, 0, len(keys))
+	for _, fk := range keys {
+		if fk.refTable == nil {
+			continue
+		}
+
+		c := jen.Id(fk.goName)
+		if fk.pointer {
+			c.Op("*")
+		}
+		c.Id(goStructName(fk.refTable))
+
+		foreignKey, references := goFieldName(fk.field), refFieldName(fk)
+		tag := map[string]string{
+			"gorm": "foreignKey:" + foreignKey + ";references:" + references,
+		}
+		if constraint := fk.constraint(); constraint != "" {
+			tag["gorm"] += ";constraint:" + constraint
+		}
+		if options.GormV1 {
+			tag["gorm"] = "foreignkey:" + foreignKey + ";association_foreignkey:" + references
+		}
+		if options.GenJsonTag {
+			tag["json"] = associationJsonName(options, fk.goName) + ",omitempty"
+		}
+		cs = append(cs, c.Tag(tag))
+	}
+	return cs
+}
+
+// associationJsonName json key of association field following options.JsonTagStyle as if it were a column,
+// e.g. userProfile, user_profile of UserProfile
+func associationJsonName(options *Options, goName string) string {
+	return jsonName(options, SnakeCase(goName))
+}
+
+// associationKeys foreign keys and inferred foreign keys of table
+func (t *Table) associationKeys() []*ForeignKey {
+	if len(t.inferredKeys) == 0 {
+		return t.ForeignKeys
+	}
+	keys := make([]*ForeignKey, 0, len(t.ForeignKeys)+len(t.inferredKeys))
+	return append(append(keys, t.ForeignKeys...), t.inferredKeys...)
+}
+
+// resolveInferredKeys foreign keys of options.InferRelations, column `<name>_id` without foreign key refers the
+// single primary key of table `<name>`, or its plural `<name>s`, with table prefix of the filter
+func resolveInferredKeys(options *Options, tables []*Table, byName map[string]*Table) {
+	for _, table := range tables {
+		table.inferredKeys = nil
+	}
+	if !options.InferRelations {
+		return
+	}
+
+	for _, table := range tables {
+		keyed := make(map[string]bool, len(table.ForeignKeys))
+		for _, fk := range table.ForeignKeys {
+			keyed[fk.Column] = true
+		}
+
+		for _, f := range table.Fields {
+			if keyed[f.Field] || !strings.HasSuffix(f.Field, "_id") {
+				continue
+			}
+			name := strings.TrimSuffix(f.Field, "_id")
+			for _, candidate := range []string{name, name + "s", table.Prefix + name, table.Prefix + name + "s"} {
+				ref, ok := byName[candidate]
+				if !ok {
+					continue
+				}
+				if pks := primaryKeys(ref.Fields); len(pks) == 1 {
+					table.inferredKeys = append(table.inferredKeys, &ForeignKey{
+						Column:    f.Field,
+						RefTable:  ref.Name,
+						RefColumn: pks[0].Field,
+					})
+				}
+				break
+			}
+		}
+	}
+}
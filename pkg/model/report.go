@@ -0,0 +1,186 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeMarkdown write markdown data dictionary of tables to options.MarkdownFile
+func writeMarkdown(options *Options, tables []*Table) error {
+	cell := func(v string) string {
+		return strings.ReplaceAll(OneLine(v), "|", `\|`)
+	}
+
+	var b strings.Builder
+	b.WriteString("# data dictionary\n\n")
+	fmt.Fprintf(&b, "generated by database-struct @%v\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	for _, table := range tables {
+		fmt.Fprintf(&b, "\n## %s\n\n", table.Name)
+		if table.Comment != "" {
+			fmt.Fprintf(&b, "%s\n\n", OneLine(table.Comment))
+		}
+		b.WriteString("| column | type | nullable | default | key | comment |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, f := range table.Fields {
+			comment := f.Comment
+			if len(f.EnumValues) > 0 {
+				comment = strings.TrimSpace(comment + " values: " + strings.Join(f.EnumValues, ", "))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", cell(f.Field), cell(f.Type), f.Null, cell(f.Default), f.Key, cell(comment))
+		}
+	}
+
+	return ioutil.WriteFile(options.MarkdownFile, []byte(b.String()), 0600)
+}
+
+// openApiSchema schema object of openapi 3.0
+type openApiSchema struct {
+	Ref         string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string                    `json:"format,omitempty" yaml:"format,omitempty"`
+	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Nullable    bool                      `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Enum        []interface{}             `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Properties  map[string]*openApiSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// openApiSchemas component schemas of go structs of tables keyed by struct name, property names are
+// json keys of the structs, nullable columns are nullable, columns not omitted when empty are required
+func openApiSchemas(options *Options, tables []*Table) map[string]*openApiSchema {
+	schemas := make(map[string]*openApiSchema, len(tables))
+	for _, table := range tables {
+		s := &openApiSchema{Type: "object", Description: OneLine(table.Comment), Properties: make(map[string]*openApiSchema)}
+		for _, f := range table.Fields {
+			if secretColumn(options, table, f) {
+				continue
+			}
+			if g := f.embed; g != nil {
+				name := g.Name
+				if options.GenJsonTag {
+					name = g.jsonName(options)
+				}
+				s.Properties[name] = &openApiSchema{Ref: "#/components/schemas/" + g.Name}
+				if schemas[g.Name] == nil {
+					schemas[g.Name] = openApiEmbedSchema(options, g)
+				}
+				continue
+			}
+
+			name, required := openApiProperty(options, f)
+			s.Properties[name] = openApiFieldSchema(options, f)
+			if required {
+				s.Required = append(s.Required, name)
+			}
+		}
+		schemas[goStructName(table)] = s
+	}
+	return schemas
+}
+
+// openApiEmbedSchema schema of embedded struct of columns with prefix
+func openApiEmbedSchema(options *Options, g *embedGroup) *openApiSchema {
+	s := &openApiSchema{Type: "object", Description: fmt.Sprintf("embedded columns with prefix %s", g.Prefix),
+		Properties: make(map[string]*openApiSchema)}
+	for _, f := range g.fields {
+		name, required := openApiProperty(options, f)
+		s.Properties[name] = openApiFieldSchema(options, f)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// openApiProperty json key of field, and whether it's always present
+func openApiProperty(options *Options, f *Field) (string, bool) {
+	if !options.GenJsonTag {
+		return goFieldName(f), true
+	}
+	tag := jsonTag(options, f)
+	return strings.Split(tag, ",")[0], !f.Nullable && !strings.Contains(tag, ",omitempty")
+}
+
+// openApiFieldSchema schema of go type of field, enum values of enum fields, unknown types accept any value
+func openApiFieldSchema(options *Options, f *Field) *openApiSchema {
+	s := &openApiSchema{Description: OneLine(f.Comment), Nullable: f.Nullable}
+	if e := f.goEnum; e != nil {
+		if e.Ints != nil {
+			s.Type = "integer"
+			for _, v := range e.Ints {
+				s.Enum = append(s.Enum, v)
+			}
+			return s
+		}
+		s.Type = "string"
+		if !e.set {
+			for _, v := range e.Values {
+				s.Enum = append(s.Enum, v)
+			}
+		}
+		return s
+	}
+	if options.GenJsonTag && jsonString(options, f) {
+		s.Type, s.Format = "string", "int64"
+		return s
+	}
+
+	goType := f.GoType
+	if v, ok := typeOverride(options, f); ok {
+		goType = v
+	}
+	switch goType {
+	case "bool":
+		s.Type = "boolean"
+	case "int8", "int16", "int32", "uint8", "uint16":
+		s.Type, s.Format = "integer", "int32"
+	case "int", "int64", "uint", "uint32", "uint64":
+		s.Type, s.Format = "integer", "int64"
+	case "float32":
+		s.Type, s.Format = "number", "float"
+	case "float64":
+		s.Type, s.Format = "number", "double"
+	case "string":
+		s.Type = "string"
+	case "[]byte":
+		s.Type, s.Format = "string", "byte"
+	case "time.Time":
+		s.Type, s.Format = "string", "date-time"
+	}
+	return s
+}
+
+// writeOpenApi write openapi document of component schemas of tables to options.OpenApiFile,
+// json if file ext is .json, yaml otherwise
+func writeOpenApi(options *Options, tables []*Table) error {
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":   "database-struct models",
+			"version": "1.0.0",
+		},
+		"paths":      map[string]interface{}{},
+		"components": map[string]interface{}{"schemas": openApiSchemas(options, tables)},
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if strings.EqualFold(filepath.Ext(options.OpenApiFile), ".json") {
+		b, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		b, err = yaml.Marshal(doc)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(options.OpenApiFile, b, 0600)
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"errors"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// ErrModelImportPath repository interfaces in separate package refer models by import path
+var ErrModelImportPath = errors.New("model import path is required by repository interfaces")
+
+// ErrRepositoryRequired repository registry refers constructors of repository implementations
+var ErrRepositoryRequired = errors.New("repository implementations are required by repository registry")
+
+// repositoryDir dir of repository interfaces relative to model dir, and its package name
+func repositoryDir(options *Options) (string, string) {
+	dir := options.RepositoryDir
+	if dir == "" {
+		dir = "repository"
+	}
+	return dir, path.Base(filepath.ToSlash(dir))
+}
+
+// goRepositoryInterface `<Name>Repository` interface of crud methods, keyed by primary key and
+// not null unique columns, implementations are provided by users, or generated ones of options.GenRepository
+func goRepositoryInterface(options *Options, table *Table) jen.Code {
+	name := goStructName(table)
+	model := func() *jen.Statement { return jen.Op("*").Qual(options.ModelImportPath, name) }
+	ctx := func() *jen.Statement { return jen.Id("ctx").Qual("context", "Context") }
+
+	methods := []jen.Code{
+		jen.Id("Create").Params(ctx(), jen.Id("m").Add(model())).Error(),
+	}
+
+	if pks := primaryKeys(table.Fields); len(pks) > 0 {
+		methods = append(methods,
+			jen.Id("Get").Params(append([]jen.Code{ctx()}, repositoryParams(options, pks)...)...).Params(model(), jen.Error()),
+			jen.Id("Update").Params(ctx(), jen.Id("m").Add(model())).Error(),
+			jen.Id("Delete").Params(append([]jen.Code{ctx()}, repositoryParams(options, pks)...)...).Error(),
+		)
+	}
+
+	for _, f := range uniqueKeys(table) {
+		methods = append(methods,
+			jen.Id("GetBy"+goFieldName(f)).Params(append([]jen.Code{ctx()}, repositoryParams(options, []*Field{f})...)...).Params(model(), jen.Error()),
+		)
+	}
+
+	methods = append(methods,
+		jen.Id("List").Params(ctx(), jen.List(jen.Id("offset"), jen.Id("limit")).Int()).Params(jen.Index().Add(model()), jen.Error()),
+	)
+
+	return jen.Commentf("%sRepository repository of %s, table: %s", name, name, table.Name).Line().
+		Type().Id(name + "Repository").Interface(methods...)
+}
+
+// uniqueKeys not null unique columns of table
+func uniqueKeys(table *Table) []*Field {
+	fields := make([]*Field, 0, 2)
+	for _, f := range table.Fields {
+		if f.Key == "UNI" && !f.Nullable && f.embed == nil {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// repositoryLocals names of repository method bodies, params of the same name get `_` suffix
+var repositoryLocals = map[string]bool{
+	"ctx": true, "m": true, "r": true, "err": true, "ms": true,
+	"db": true, "row": true, "rows": true, "res": true, "insertId": true,
+	"context": true, "sql": true,
+}
+
+// repositoryParam param name of column value, lower camel go field name, keywords, locals of repository
+// methods and the model package name get `_` suffix
+func repositoryParam(options *Options, f *Field) string {
+	field := goFieldName(f)
+	param := strings.ToLower(field[:1]) + field[1:]
+	if token.IsKeyword(param) || repositoryLocals[param] || param == packageName(options.ModelImportPath) {
+		param += "_"
+	}
+	return param
+}
+
+// repositoryParams method params of column values, named by lower camel go field names
+func repositoryParams(options *Options, fields []*Field) []jen.Code {
+	params := make([]jen.Code, 0, len(fields))
+	for _, f := range fields {
+		c := jen.Id(repositoryParam(options, f))
+		if f.goEnum != nil {
+			c.Qual(options.ModelImportPath, f.goEnum.Name)
+		} else {
+			goFieldType(options, f, c)
+		}
+		params = append(params, c)
+	}
+	return params
+}
+
+// goRepositoryImpl gorm implementation of `<Name>Repository` and its constructor `New<Name>Repository`
+func goRepositoryImpl(options *Options, table *Table) jen.Code {
+	name := goStructName(table)
+	impl := "gorm" + name + "Repository"
+	gormPkg := gormPackage(options)
+	model := func() *jen.Statement { return jen.Op("*").Qual(options.ModelImportPath, name) }
+	ctx := func() *jen.Statement { return jen.Id("ctx").Qual("context", "Context") }
+	method := func(id string) *jen.Statement {
+		return jen.Func().Params(jen.Id("r").Op("*").Id(impl)).Id(id)
+	}
+	db := func() *jen.Statement {
+		if options.GormV1 {
+			return jen.Id("r").Dot("db")
+		}
+		return jen.Id("r").Dot("db").Dot("WithContext").Call(jen.Id("ctx"))
+	}
+	where := func(fields []*Field) *jen.Statement {
+		conds := make([]string, 0, len(fields))
+		args := make([]jen.Code, 0, len(fields)+1)
+		args = append(args, jen.Null())
+		for _, f := range fields {
+			conds = append(conds, f.Field+" = ?")
+			args = append(args, jen.Id(repositoryParam(options, f)))
+		}
+		args[0] = jen.Lit(strings.Join(conds, " AND "))
+		return db().Dot("Where").Call(args...)
+	}
+	get := func(id string, fields []*Field) *jen.Statement {
+		return method(id).Params(append([]jen.Code{ctx()}, repositoryParams(options, fields)...)...).Params(model(), jen.Error()).Block(
+			jen.Id("m").Op(":=").New(jen.Qual(options.ModelImportPath, name)),
+			jen.If(jen.Err().Op(":=").Add(where(fields)).Dot("First").Call(jen.Id("m")).Dot("Error"), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Err()),
+			),
+			jen.Return(jen.Id("m"), jen.Nil()),
+		)
+	}
+
+	c := jen.Commentf("%s gorm implementation of %sRepository", impl, name).Line().
+		Type().Id(impl).Struct(jen.Id("db").Op("*").Qual(gormPkg, "DB")).Line().Line().
+		Commentf("New%sRepository gorm implementation of %sRepository", name, name).Line().
+		Func().Id("New"+name+"Repository").Params(jen.Id("db").Op("*").Qual(gormPkg, "DB")).Id(name+"Repository").Block(
+		jen.Return(jen.Op("&").Id(impl).Values(jen.Dict{jen.Id("db"): jen.Id("db")})),
+	).Line().Line().
+		Add(method("Create")).Params(ctx(), jen.Id("m").Add(model())).Error().Block(
+		jen.Return(db().Dot("Create").Call(jen.Id("m")).Dot("Error")),
+	)
+
+	if pks := primaryKeys(table.Fields); len(pks) > 0 {
+		c = c.Line().Line().Add(get("Get", pks)).Line().Line().
+			Add(method("Update")).Params(ctx(), jen.Id("m").Add(model())).Error().Block(
+			jen.Return(db().Dot("Save").Call(jen.Id("m")).Dot("Error")),
+		).Line().Line().
+			Add(method("Delete")).Params(append([]jen.Code{ctx()}, repositoryParams(options, pks)...)...).Error().Block(
+			jen.Return(where(pks).Dot("Delete").Call(jen.Op("&").Qual(options.ModelImportPath, name).Values()).Dot("Error")),
+		)
+	}
+
+	for _, f := range uniqueKeys(table) {
+		c = c.Line().Line().Add(get("GetBy"+goFieldName(f), []*Field{f}))
+	}
+
+	return c.Line().Line().
+		Add(method("List")).Params(ctx(), jen.List(jen.Id("offset"), jen.Id("limit")).Int()).Params(jen.Index().Add(model()), jen.Error()).Block(
+		jen.Var().Id("ms").Index().Add(model()),
+		jen.Err().Op(":=").Add(db()).Dot("Offset").Call(jen.Id("offset")).Dot("Limit").Call(jen.Id("limit")).Dot("Find").Call(jen.Op("&").Id("ms")).Dot("Error"),
+		jen.Return(jen.Id("ms"), jen.Err()),
+	)
+}
+
+// goRepositoryRegistry `Repositories` gorm or database/sql repository factories keyed by table name,
+// e.g. for generic crud by table name
+func goRepositoryRegistry(options *Options, tables []*Table) jen.Code {
+	driver, dbPkg := "gorm", gormPackage(options)
+	if sqlRepository(options) {
+		driver, dbPkg = "database/sql", "database/sql"
+	}
+	factories := make(jen.Dict, len(tables))
+	for _, table := range tables {
+		factories[jen.Lit(table.Name)] = jen.Func().Params(jen.Id("db").Op("*").Qual(dbPkg, "DB")).Interface().Block(
+			jen.Return(jen.Id("New" + goStructName(table) + "Repository").Call(jen.Id("db"))),
+		)
+	}
+	return jen.Commentf("Repositories %s repository factories keyed by table name", driver).Line().
+		Var().Id("Repositories").Op("=").Map(jen.String()).Func().Params(jen.Op("*").Qual(dbPkg, "DB")).Interface().Values(factories)
+}
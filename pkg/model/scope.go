@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// gormPackage import path of gorm of options
+func gormPackage(options *Options) string {
+	if options.GormV1 {
+		return "github.com/jinzhu/gorm"
+	}
+	return "gorm.io/gorm"
+}
+
+// goScopes gorm scope funcs of soft delete `deleted_at`, boolean columns and `active` value of enum columns,
+// e.g. NotDeletedUsers, IsVipUsers, StatusActiveUsers; nil if table has none
+func goScopes(options *Options, name string, table *Table) jen.Code {
+	gormPkg := gormPackage(options)
+	var c *jen.Statement
+	scope := func(scopeName, comment string, where ...jen.Code) {
+		if c == nil {
+			c = jen.Null()
+		} else {
+			c.Line().Line()
+		}
+		c.Comment(scopeName+" gorm scope "+comment).Line().
+			Func().Id(scopeName).Params(jen.Id("db").Op("*").Qual(gormPkg, "DB")).Op("*").Qual(gormPkg, "DB").Block(
+			jen.Return(jen.Id("db").Dot("Where").Call(where...)),
+		)
+	}
+
+	for _, f := range table.Fields {
+		switch {
+		case f.Field == "deleted_at" && f.Nullable:
+			scope("NotDeleted"+name+"s", "of rows not soft deleted", jen.Lit("deleted_at IS NULL"))
+		case f.GoType == "bool" || options.BoolStrategy == "" && f.Type == "tinyint(1)":
+			scope(goFieldName(f)+name+"s", "of rows "+f.Field+" is true", jen.Lit(f.Field+" = ?"), jen.True())
+		case f.goEnum != nil && f.goEnum.Ints == nil:
+			for i, v := range f.goEnum.Values {
+				if strings.EqualFold(v, "active") {
+					scope(goFieldName(f)+"Active"+name+"s", "of rows "+f.Field+" is "+v, jen.Lit(f.Field+" = ?"), jen.Id(f.goEnum.constNames()[i]))
+				}
+			}
+		}
+	}
+	if c == nil {
+		return nil
+	}
+	return c
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// secretColumn returns true if column matches options.SecretColumns, pattern with dot matches `table.column`
+func secretColumn(options *Options, table *Table, field *Field) bool {
+	return columnMatch(options.SecretColumns, table, field)
+}
+
+// columnMatch returns true if column matches any of path.Match patterns, pattern with dot matches `table.column`
+func columnMatch(patterns []string, table *Table, field *Field) bool {
+	for _, pattern := range patterns {
+		name := field.Field
+		if strings.Contains(pattern, ".") {
+			name = fmt.Sprint(table.Name, ".", field.Field)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
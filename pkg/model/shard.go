@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrShardOption option refers models of other shards, which are in other packages
+var ErrShardOption = errors.New("option not supported by letter shards")
+
+// checkShardOptions returns ErrShardOption for options generating code that refers models across shards,
+// or files out of shards
+func checkShardOptions(options *Options) error {
+	if !options.ShardOutputByLetter {
+		return nil
+	}
+	conflicts := []struct {
+		set  bool
+		name string
+	}{
+		{options.ModelSingleFile, "ModelSingleFile"},
+		{options.FilePerPrefixGroup, "FilePerPrefixGroup"},
+		{options.GenRelations, "GenRelations"},
+		{options.HistorySuffix != "", "HistorySuffix"},
+		{options.GenRepositoryInterface, "GenRepositoryInterface"},
+		{options.GenRepository, "GenRepository"},
+		{options.GenTests, "GenTests"},
+		{options.EnumFile != "", "EnumFile"},
+		{options.ConstantsFile != "", "ConstantsFile"},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return fmt.Errorf("%w: %s", ErrShardOption, c.name)
+		}
+	}
+	return nil
+}
+
+// shardDir sub dir of table by first letter of its struct name, e.g. u of User and t of _tmp, 0 if not a letter,
+// dirs starting with _ are ignored by the go tool
+func shardDir(table *Table) string {
+	name := goStructName(table)
+	if name == "" {
+		return "0"
+	}
+	ch := strings.ToLower(name[:1])
+	if ch < "a" || ch > "z" {
+		return "0"
+	}
+	return ch
+}
+
+// letterShards tables grouped by shard dir, dirs are sorted
+func letterShards(tables []*Table) (map[string][]*Table, []string) {
+	shards := make(map[string][]*Table)
+	dirs := make([]string, 0, 26)
+	for _, table := range tables {
+		dir := shardDir(table)
+		if _, ok := shards[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		shards[dir] = append(shards[dir], table)
+	}
+	sort.Strings(dirs)
+	return shards, dirs
+}
+
+// shardShared enums and embedded groups used by tables of a shard, each shard package has its own copy
+func shardShared(tables []*Table, enums []*enum, embeds []*embedGroup) ([]*enum, []*embedGroup) {
+	in := make(map[*Table]bool, len(tables))
+	for _, table := range tables {
+		in[table] = true
+	}
+
+	shardEnums := make([]*enum, 0, len(enums))
+	for _, e := range enums {
+		for _, t := range e.tables {
+			if in[t] {
+				shardEnums = append(shardEnums, e)
+				break
+			}
+		}
+	}
+
+	used := make(map[*embedGroup]bool)
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			if f.embed != nil {
+				used[f.embed] = true
+			}
+		}
+	}
+	shardEmbeds := make([]*embedGroup, 0, len(embeds))
+	for _, g := range embeds {
+		if used[g] {
+			shardEmbeds = append(shardEmbeds, g)
+		}
+	}
+	return shardEnums, shardEmbeds
+}
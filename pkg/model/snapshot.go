@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// DumpTables write tables as json to file
+func DumpTables(file string, tables []*Table) error {
+	b, err := json.MarshalIndent(tables, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0600)
+}
+
+// LoadTables read tables from json file written by DumpTables
+func LoadTables(file string) ([]*Table, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*Table
+	err = json.Unmarshal(b, &tables)
+	return tables, err
+}
+
+// SchemaTables returns tables of options.SchemaFile, the schema is introspected from db and
+// the file rewritten if options.UpdateSnapshot is set, so generation needs no db access otherwise
+func SchemaTables(options *Options) ([]*Table, error) {
+	if !options.UpdateSnapshot {
+		if options.Verbose {
+			l.Println("load tables from", options.SchemaFile)
+		}
+		return LoadTables(options.SchemaFile)
+	}
+
+	tables, err := DbStruct(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Verbose {
+		l.Println("update schema snapshot", options.SchemaFile)
+	}
+	return tables, DumpTables(options.SchemaFile, tables)
+}
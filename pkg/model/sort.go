@@ -0,0 +1,64 @@
+package model
+
+import "github.com/dave/jennifer/jen"
+
+// goSortHelpers `<Name>sBy<Field>` slice types implementing sort.Interface, for primary key and indexed columns,
+// nullable and unordered columns are skipped, so are time columns of options.TimeType, nil if table has none
+func goSortHelpers(options *Options, name string, table *Table) jen.Code {
+	base := baseField(options, table)
+	var c *jen.Statement
+	for _, f := range table.Fields {
+		if f.Key == "" || f.Nullable || f == base || f.embed != nil {
+			continue
+		}
+		if _, ok := typeOverride(options, f); ok {
+			continue
+		}
+
+		field := goFieldName(f)
+		typeName := name + "sBy" + field
+		recv := receiverName(options, typeName, "s")
+		// index params must not shadow the receiver, e.g. receiver i of options.ReceiverName
+		i, j := "i", "j"
+		if recv == i || recv == j {
+			i, j = "x", "y"
+		}
+		a, b := jen.Id(recv).Index(jen.Id(i)).Dot(field), jen.Id(recv).Index(jen.Id(j)).Dot(field)
+
+		var less *jen.Statement
+		switch f.GoType {
+		case "int", "uint", "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64",
+			"float32", "float64", "string":
+			less = a.Op("<").Add(b)
+		case "time.Time":
+			if options.TimeType != "" {
+				continue
+			}
+			less = a.Dot("Before").Call(b)
+		default:
+			continue
+		}
+
+		if c == nil {
+			c = jen.Null()
+		} else {
+			c.Line().Line()
+		}
+		c.Commentf("%s sorts []%s by %s, implements sort.Interface", typeName, receiverType(options, name).GoString(), field).Line().
+			Type().Id(typeName).Index().Add(receiverType(options, name)).Line().Line().
+			Func().Params(jen.Id(recv).Id(typeName)).Id("Len").Params().Int().Block(
+			jen.Return(jen.Len(jen.Id(recv))),
+		).Line().Line().
+			Func().Params(jen.Id(recv).Id(typeName)).Id("Less").Params(jen.List(jen.Id(i), jen.Id(j)).Int()).Bool().Block(
+			jen.Return(less),
+		).Line().Line().
+			Func().Params(jen.Id(recv).Id(typeName)).Id("Swap").Params(jen.List(jen.Id(i), jen.Id(j)).Int()).Block(
+			jen.List(jen.Id(recv).Index(jen.Id(i)), jen.Id(recv).Index(jen.Id(j))).Op("=").
+				List(jen.Id(recv).Index(jen.Id(j)), jen.Id(recv).Index(jen.Id(i))),
+		)
+	}
+	if c == nil {
+		return nil
+	}
+	return c
+}
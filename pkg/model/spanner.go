@@ -0,0 +1,155 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrSpannerNotSupported = errors.New("spanner not supported, build with tag `spanner`")
+
+// spannerColumn column of spanner INFORMATION_SCHEMA.COLUMNS
+type spannerColumn struct {
+	TableName       string
+	ParentTableName string
+	ColumnName      string
+	SpannerType     string
+	IsNullable      string
+	OrdinalPosition int64
+	PrimaryKey      bool
+}
+
+// querySpannerColumns query columns of tables in default schema ordered by table and position,
+// set by the `spanner` build tag, dsn is database name, e.g. projects/p/instances/i/databases/d
+var querySpannerColumns = func(ctx context.Context, options *Options, filter *Filter) ([]*spannerColumn, error) {
+	return nil, ErrSpannerNotSupported
+}
+
+// spanner google cloud spanner, GoogleSQL dialect
+type spanner struct{}
+
+func (t *spanner) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
+	if options.Verbose {
+		l.Println("spanner dump db struct")
+	}
+
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		columns, err := querySpannerColumns(ctx, options, filter)
+		if err != nil {
+			return nil, err
+		}
+		return t.tables(columns, filter), nil
+	})
+
+	if options.Verbose && err == nil {
+		l.Println("dump completed, table count:", len(tables))
+	}
+
+	return
+}
+
+// tables group columns to tables, spanner has no auto increment, interleaved table is kept flat
+func (t *spanner) tables(columns []*spannerColumn, filter *Filter) []*Table {
+	tables := make([]*Table, 0, 16)
+	var tb *Table
+	for _, it := range columns {
+		if tb == nil || tb.Name != it.TableName {
+			tb = &Table{Name: it.TableName}
+			if it.ParentTableName != "" {
+				tb.Comment = fmt.Sprint("interleaved in ", it.ParentTableName)
+			}
+			if filter != nil {
+				tb.Prefix = filter.TablePrefix
+			}
+			tables = append(tables, tb)
+		}
+
+		field := &Field{
+			Field:    it.ColumnName,
+			Type:     it.SpannerType,
+			Null:     strings.ToUpper(it.IsNullable),
+			Nullable: strings.ToUpper(it.IsNullable) == "YES",
+		}
+		if it.PrimaryKey {
+			field.Key = "PRI"
+		}
+		field.GoType, _ = t.getGoType(field.Type)
+		tb.Fields = append(tb.Fields, field)
+	}
+
+	parents := make(map[string]string)
+	for _, it := range columns {
+		parents[it.TableName] = it.ParentTableName
+	}
+	for _, tb := range tables {
+		tb.Ddl = t.tableDdl(tb, parents[tb.Name])
+	}
+	return tables
+}
+
+// tableDdl reconstruct create table statement from fields
+func (t *spanner) tableDdl(table *Table, parent string) string {
+	lines := make([]string, 0, len(table.Fields))
+	pks := make([]string, 0, 1)
+	for _, f := range table.Fields {
+		line := fmt.Sprintf("  %s %s", f.Field, f.Type)
+		if !f.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+		if f.Key == "PRI" {
+			pks = append(pks, f.Field)
+		}
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE %s (\n%s\n) PRIMARY KEY (%s)", table.Name, strings.Join(lines, ",\n"), strings.Join(pks, ", "))
+	if parent != "" {
+		ddl += fmt.Sprint(",\n  INTERLEAVE IN PARENT ", parent)
+	}
+	return ddl
+}
+
+var spannerArrayRegexp = regexp.MustCompile(`^ARRAY<(.+)>$`)
+
+func (t *spanner) getGoType(dbType string) (string, bool) {
+	if m := spannerArrayRegexp.FindStringSubmatch(dbType); m != nil {
+		if goType, ok := t.getGoType(m[1]); ok {
+			return "[]" + goType, true
+		}
+		return "", false
+	}
+
+	// 精确匹配
+	if v, ok := typeSpannerDic[dbType]; ok {
+		return v, true
+	}
+
+	// 正则匹配
+	for _, v := range typeSpannerMatch {
+		if ok, _ := regexp.MatchString(v[0], dbType); ok {
+			return v[1], true
+		}
+	}
+
+	return "", false
+}
+
+// typeSpannerDic matching type
+var typeSpannerDic = map[string]string{
+	"INT64":     "int64",
+	"FLOAT32":   "float32",
+	"FLOAT64":   "float64",
+	"NUMERIC":   "float64",
+	"BOOL":      "bool",
+	"JSON":      "string",
+	"DATE":      "time.Time",
+	"TIMESTAMP": "time.Time",
+}
+
+// typeSpannerMatch regx match types
+var typeSpannerMatch = [][]string{
+	{`^STRING[(](\d+|MAX)[)]$`, "string"},
+	{`^BYTES[(](\d+|MAX)[)]$`, "[]byte"},
+}
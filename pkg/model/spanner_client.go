@@ -0,0 +1,69 @@
+//go:build spanner
+// +build spanner
+
+package model
+
+import (
+	"context"
+	"time"
+
+	gspanner "cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+func init() {
+	querySpannerColumns = func(ctx context.Context, options *Options, filter *Filter) ([]*spannerColumn, error) {
+		client, err := gspanner.NewClient(ctx, options.Dsn)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+
+		sql := `SELECT c.TABLE_NAME, IFNULL(t.PARENT_TABLE_NAME, '') AS PARENT_TABLE_NAME, c.COLUMN_NAME, c.SPANNER_TYPE,
+       c.IS_NULLABLE, c.ORDINAL_POSITION,
+       EXISTS(SELECT 1 FROM INFORMATION_SCHEMA.INDEX_COLUMNS i
+              WHERE i.TABLE_SCHEMA = c.TABLE_SCHEMA AND i.TABLE_NAME = c.TABLE_NAME
+                AND i.INDEX_TYPE = 'PRIMARY_KEY' AND i.COLUMN_NAME = c.COLUMN_NAME) AS PRIMARY_KEY
+FROM INFORMATION_SCHEMA.COLUMNS c
+         JOIN INFORMATION_SCHEMA.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE c.TABLE_SCHEMA = '' AND t.TABLE_TYPE = 'BASE TABLE'`
+		params := map[string]interface{}{}
+		if filter != nil {
+			l.Println("filter table_name like", filter.TableNamePattern)
+			sql += " AND c.TABLE_NAME LIKE @pattern"
+			params["pattern"] = filter.TableNamePattern
+		}
+		if len(options.Exclude) > 0 {
+			sql += " AND c.TABLE_NAME NOT IN UNNEST(@exclude)"
+			params["exclude"] = options.Exclude
+		}
+		sql += " ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION"
+
+		start := time.Now()
+		iter := client.Single().Query(ctx, gspanner.Statement{SQL: sql, Params: params})
+		defer iter.Stop()
+
+		columns := make([]*spannerColumn, 0, 64)
+		for {
+			row, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			c := new(spannerColumn)
+			err = row.Columns(&c.TableName, &c.ParentTableName, &c.ColumnName, &c.SpannerType,
+				&c.IsNullable, &c.OrdinalPosition, &c.PrimaryKey)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, c)
+		}
+		if options.LogSQL {
+			logSQL(time.Since(start), sql, params, len(columns))
+		}
+		return columns, nil
+	}
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type sqlite struct{}
+
+func (t *sqlite) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
+	// sqlite creates missing database file on connect, in-memory and uri dsn are left to the driver,
+	// e.g. file:test.db?mode=ro fails on connect then
+	if options.DB == nil && !t.memoryOrURI(options.Dsn) {
+		name := t.databaseName(options.Dsn)
+		if _, err = os.Stat(name); err != nil {
+			err = fmt.Errorf("database %q not found", name)
+			return
+		}
+	}
+
+	var db *gorm.DB
+	db, err = connect(options)
+	if err != nil {
+		return
+	}
+
+	if options.Verbose {
+		l.Println("sqlite dump db struct")
+	}
+
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		return t.filterTables(ctx, db, filter, options)
+	})
+
+	if options.Verbose && err == nil {
+		l.Println("dump completed, table count:", len(tables))
+	}
+
+	return
+}
+
+// databaseName database file of dsn, e.g. file:test.db?cache=shared
+func (t *sqlite) databaseName(dsn string) string {
+	dsn = strings.TrimPrefix(dsn, "file:")
+	if i := strings.Index(dsn, "?"); i >= 0 {
+		dsn = dsn[:i]
+	}
+	return dsn
+}
+
+// memoryOrURI returns true if dsn is an in-memory database or a uri, e.g. :memory:, file:test.db?mode=memory
+func (t *sqlite) memoryOrURI(dsn string) bool {
+	return dsn == ":memory:" || strings.HasPrefix(dsn, "file:")
+}
+
+func (t *sqlite) filterTables(ctx context.Context, db *gorm.DB, filter *Filter, options *Options) (tables []*Table, err error) {
+	type sqliteTable struct {
+		Name string `gorm:"column:name"`
+		Sql  string `gorm:"column:sql"`
+	}
+
+	var dbTables []*sqliteTable
+
+	tdb := db.Table("sqlite_master").
+		Select("name, sql").
+		Where("type = 'table' and name not like 'sqlite_%'")
+
+	if filter != nil {
+		l.Println("filter table_name like", filter.TableNamePattern)
+		tdb = tdb.Where("name like ?", filter.TableNamePattern)
+	}
+
+	if len(options.Exclude) > 0 {
+		tdb = tdb.Where("name not in(?)", options.Exclude)
+	}
+
+	err = tdb.Order("name").Find(&dbTables).Error
+	if err != nil {
+		return
+	}
+
+	tables = make([]*Table, 0, len(dbTables))
+	for _, it := range dbTables {
+		tb := &Table{
+			Schema: "main",
+			Name:   it.Name,
+			Ddl:    it.Sql,
+		}
+		if filter != nil {
+			tb.Prefix = filter.TablePrefix
+		}
+		tables = append(tables, tb)
+	}
+
+	err = introspectTables(ctx, options, tables, func(tb *Table) (err error) {
+		tb.Fields, err = t.tableFields(db, tb.Name)
+		return
+	})
+	return
+}
+
+func (t *sqlite) tableFields(db *gorm.DB, name string) (fields []*Field, err error) {
+	type sqliteField struct {
+		Name    string  `gorm:"column:name"`
+		Type    string  `gorm:"column:type"`
+		NotNull bool    `gorm:"column:notnull"`
+		Default *string `gorm:"column:dflt_value"`
+		Pk      int     `gorm:"column:pk"`
+	}
+
+	var dbFields []*sqliteField
+	err = db.Raw(fmt.Sprintf("pragma table_info(%s)", t.quote(name))).Scan(&dbFields).Error
+	if err != nil {
+		return
+	}
+
+	pks := 0
+	for _, it := range dbFields {
+		if it.Pk > 0 {
+			pks++
+		}
+	}
+
+	fields = make([]*Field, 0, len(dbFields))
+	for _, it := range dbFields {
+		// pragma reports notnull 0 of primary keys unless declared, rowid alias is never null,
+		// null of other primary keys is a legacy bug of sqlite
+		notNull := it.NotNull || it.Pk > 0
+		field := &Field{
+			Field:    it.Name,
+			Type:     strings.ToLower(it.Type),
+			Null:     "YES",
+			Nullable: !notNull,
+		}
+		if notNull {
+			field.Null = "NO"
+		}
+		if it.Default != nil {
+			field.Default = *it.Default
+		}
+		if it.Pk > 0 {
+			field.Key = "PRI"
+			// single `integer primary key` column is alias of rowid
+			field.AutoIncrement = pks == 1 && field.Type == "integer"
+		}
+
+		field.GoType, _ = t.getGoType(field.Type)
+
+		fields = append(fields, field)
+	}
+
+	return
+}
+
+// quote quote identifier, pragma does not accept bind parameters
+func (t *sqlite) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// getGoType go type of column type affinity, see https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+func (t *sqlite) getGoType(dbType string) (string, bool) {
+	switch {
+	case strings.Contains(dbType, "int"):
+		return "int64", true
+	case strings.Contains(dbType, "char"), strings.Contains(dbType, "clob"), strings.Contains(dbType, "text"):
+		return "string", true
+	case strings.Contains(dbType, "blob"):
+		return "[]byte", true
+	case strings.Contains(dbType, "real"), strings.Contains(dbType, "floa"), strings.Contains(dbType, "doub"):
+		return "float64", true
+	}
+	// numeric affinity, or no declared type
+	return "string", true
+}
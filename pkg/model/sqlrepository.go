@@ -0,0 +1,369 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	RepositoryGorm = "gorm"
+	RepositorySql  = "sql"
+)
+
+// sqlRepository returns true if repository implementations use database/sql, see Options.RepositoryDriver
+func sqlRepository(options *Options) bool {
+	return options.RepositoryDriver == RepositorySql
+}
+
+// sqlIdent quoted identifier of db type
+func sqlIdent(dbType, name string) string {
+	switch dbType {
+	case DbTypeMySQL, "":
+		return "`" + name + "`"
+	case DbTypeSQLServer:
+		return "[" + name + "]"
+	}
+	return `"` + name + `"`
+}
+
+// sqlPlaceholder bind var of the ith (from 1) argument of db type
+func sqlPlaceholder(dbType string, i int) string {
+	switch dbType {
+	case DbTypePostgreSQL, DbTypeRedshift:
+		return fmt.Sprint("$", i)
+	case DbTypeSQLServer:
+		return fmt.Sprint("@p", i)
+	case DbTypeOracle:
+		return fmt.Sprint(":", i)
+	}
+	return "?"
+}
+
+// sqlFieldValue field of model m of column, columns of embedded groups are fields of the embedded struct
+func sqlFieldValue(f *Field) *jen.Statement {
+	if g := f.embed; g != nil {
+		name := strings.TrimPrefix(f.Field, g.Prefix)
+		for _, it := range g.fields {
+			if it.Field == name {
+				return jen.Id("m").Dot(g.Name).Dot(goFieldName(it))
+			}
+		}
+	}
+	return jen.Id("m").Dot(goFieldName(f))
+}
+
+// sqlAutoKey auto increment primary key assigned after insert, integer field only
+func sqlAutoKey(options *Options, table *Table) *Field {
+	pks := primaryKeys(table.Fields)
+	if len(pks) != 1 || !pks[0].AutoIncrement || pks[0].Nullable || pks[0].goEnum != nil || pks[0].embed != nil {
+		return nil
+	}
+	if _, ok := typeOverride(options, pks[0]); ok || !strings.Contains(pks[0].GoType, "int") {
+		return nil
+	}
+	return pks[0]
+}
+
+// goSqlRepositoryImpl database/sql implementation of `<Name>Repository` and its constructor `New<Name>Repository`,
+// bind vars and quoting are of options.DbType, generated columns are not written
+func goSqlRepositoryImpl(options *Options, table *Table) jen.Code {
+	name := goStructName(table)
+	impl := "sql" + name + "Repository"
+	dbType := options.DbType
+	model := func() *jen.Statement { return jen.Op("*").Qual(options.ModelImportPath, name) }
+	ctx := func() *jen.Statement { return jen.Id("ctx").Qual("context", "Context") }
+	method := func(id string) *jen.Statement {
+		return jen.Func().Params(jen.Id("r").Op("*").Id(impl)).Id(id)
+	}
+	db := func() *jen.Statement { return jen.Id("r").Dot("db") }
+
+	columns := make([]string, 0, len(table.Fields))
+	scans := make([]jen.Code, 0, len(table.Fields))
+	for _, f := range table.Fields {
+		columns = append(columns, sqlIdent(dbType, f.Field))
+		scans = append(scans, jen.Op("&").Add(sqlFieldValue(f)))
+	}
+	selectSql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), sqlIdent(dbType, table.Name))
+
+	// where conditions of fields, bind vars start after n arguments
+	where := func(fields []*Field, n int) string {
+		conds := make([]string, 0, len(fields))
+		for i, f := range fields {
+			conds = append(conds, sqlIdent(dbType, f.Field)+" = "+sqlPlaceholder(dbType, n+i+1))
+		}
+		return " WHERE " + strings.Join(conds, " AND ")
+	}
+	params := func(fields []*Field) []jen.Code {
+		args := make([]jen.Code, 0, len(fields))
+		for _, f := range fields {
+			args = append(args, jen.Id(repositoryParam(options, f)))
+		}
+		return args
+	}
+	get := func(id string, fields []*Field) *jen.Statement {
+		return method(id).Params(append([]jen.Code{ctx()}, repositoryParams(options, fields)...)...).Params(model(), jen.Error()).Block(
+			jen.Return(jen.Id("r").Dot("scan").Call(db().Dot("QueryRowContext").Call(
+				append([]jen.Code{jen.Id("ctx"), jen.Lit(selectSql + where(fields, 0))}, params(fields)...)...,
+			))),
+		)
+	}
+
+	c := jen.Commentf("%s database/sql implementation of %sRepository", impl, name).Line().
+		Type().Id(impl).Struct(jen.Id("db").Op("*").Qual("database/sql", "DB")).Line().Line().
+		Commentf("New%sRepository database/sql implementation of %sRepository", name, name).Line().
+		Func().Id("New"+name+"Repository").Params(jen.Id("db").Op("*").Qual("database/sql", "DB")).Id(name+"Repository").Block(
+		jen.Return(jen.Op("&").Id(impl).Values(jen.Dict{jen.Id("db"): jen.Id("db")})),
+	).Line().Line().
+		Add(method("scan")).Params(jen.Id("row").Interface(jen.Id("Scan").Params(jen.Op("...").Interface()).Error())).Params(model(), jen.Error()).Block(
+		jen.Id("m").Op(":=").New(jen.Qual(options.ModelImportPath, name)),
+		jen.If(jen.Err().Op(":=").Id("row").Dot("Scan").Call(scans...), jen.Err().Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Err()),
+		),
+		jen.Return(jen.Id("m"), jen.Nil()),
+	).Line().Line().
+		Add(method("Create")).Params(ctx(), jen.Id("m").Add(model())).Error().Block(goSqlCreate(options, table)...)
+
+	pks := primaryKeys(table.Fields)
+	if len(pks) > 0 {
+		c = c.Line().Line().Add(get("Get", pks)).Line().Line().
+			Add(method("Update")).Params(ctx(), jen.Id("m").Add(model())).Error().Block(goSqlUpdate(options, table, pks)...).Line().Line().
+			Add(method("Delete")).Params(append([]jen.Code{ctx()}, repositoryParams(options, pks)...)...).Error().Block(
+			jen.List(jen.Id("_"), jen.Err()).Op(":=").Add(db()).Dot("ExecContext").Call(
+				append([]jen.Code{jen.Id("ctx"), jen.Lit("DELETE FROM " + sqlIdent(dbType, table.Name) + where(pks, 0))}, params(pks)...)...,
+			),
+			jen.Return(jen.Err()),
+		)
+	}
+
+	for _, f := range uniqueKeys(table) {
+		c = c.Line().Line().Add(get("GetBy"+goFieldName(f), []*Field{f}))
+	}
+
+	// sql server and oracle paginate ordered rows only
+	order := make([]string, 0, len(pks))
+	for _, f := range pks {
+		order = append(order, sqlIdent(dbType, f.Field))
+	}
+	listSql, listArgs := selectSql, []jen.Code{jen.Id("limit"), jen.Id("offset")}
+	switch dbType {
+	case DbTypeSQLServer, DbTypeOracle:
+		if len(order) == 0 {
+			order = append(order, "(SELECT NULL)")
+		}
+		listSql += fmt.Sprintf(" ORDER BY %s OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", strings.Join(order, ", "),
+			sqlPlaceholder(dbType, 1), sqlPlaceholder(dbType, 2))
+		listArgs = []jen.Code{jen.Id("offset"), jen.Id("limit")}
+	default:
+		if len(order) > 0 {
+			listSql += " ORDER BY " + strings.Join(order, ", ")
+		}
+		listSql += fmt.Sprintf(" LIMIT %s OFFSET %s", sqlPlaceholder(dbType, 1), sqlPlaceholder(dbType, 2))
+	}
+
+	return c.Line().Line().
+		Add(method("List")).Params(ctx(), jen.List(jen.Id("offset"), jen.Id("limit")).Int()).Params(jen.Index().Add(model()), jen.Error()).Block(
+		jen.List(jen.Id("rows"), jen.Err()).Op(":=").Add(db()).Dot("QueryContext").Call(append([]jen.Code{jen.Id("ctx"), jen.Lit(listSql)}, listArgs...)...),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+		jen.Defer().Id("rows").Dot("Close").Call(),
+		jen.Line(),
+		jen.Var().Id("ms").Index().Add(model()),
+		jen.For(jen.Id("rows").Dot("Next").Call()).Block(
+			jen.List(jen.Id("m"), jen.Err()).Op(":=").Id("r").Dot("scan").Call(jen.Id("rows")),
+			jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+			jen.Id("ms").Op("=").Append(jen.Id("ms"), jen.Id("m")),
+		),
+		jen.Return(jen.Id("ms"), jen.Id("rows").Dot("Err").Call()),
+	)
+}
+
+// goSqlCreate body of Create, columns with expression defaults, e.g. CURRENT_TIMESTAMP, are inserted only if their
+// fields are not zero, otherwise they are left to the db. Auto increment primary key and the defaults are read back by
+// RETURNING of postgresql, OUTPUT of sql server, otherwise the key is returned by LastInsertId and the defaults are
+// selected by primary key. Redshift reads back nothing, neither does a primary key with expression default left to the db
+func goSqlCreate(options *Options, table *Table) []jen.Code {
+	dbType := options.DbType
+	auto := sqlAutoKey(options, table)
+	columns := make([]string, 0, len(table.Fields))
+	values := make([]jen.Code, 0, len(table.Fields))
+	defaults := make([]*Field, 0, 2)
+	for _, f := range table.Fields {
+		if f == auto || f.Generated != "" {
+			continue
+		}
+		if expressionDefault(f) {
+			defaults = append(defaults, f)
+			continue
+		}
+		columns = append(columns, sqlIdent(dbType, f.Field))
+		values = append(values, sqlFieldValue(f))
+	}
+	returned := make([]*Field, 0, len(defaults)+1)
+	if auto != nil {
+		returned = append(returned, auto)
+	}
+	returned = append(returned, defaults...)
+
+	names := func(prefix string) string {
+		list := make([]string, 0, len(returned))
+		for _, f := range returned {
+			list = append(list, prefix+sqlIdent(dbType, f.Field))
+		}
+		return strings.Join(list, ", ")
+	}
+	var returning, output string
+	switch {
+	case len(returned) == 0:
+	case dbType == DbTypePostgreSQL:
+		returning = " RETURNING " + names("")
+	case dbType == DbTypeSQLServer:
+		output = " OUTPUT " + names("INSERTED.")
+	}
+
+	insert := "INSERT INTO " + sqlIdent(dbType, table.Name)
+	empty := " DEFAULT VALUES"
+	if dbType == DbTypeMySQL || dbType == "" {
+		empty = " () VALUES ()"
+	}
+
+	// query and args of the insert, static unless columns with expression defaults are inserted if set
+	var (
+		query jen.Code
+		args  []jen.Code
+		body  []jen.Code
+	)
+	if len(defaults) == 0 {
+		binds := make([]string, 0, len(columns))
+		for i := range columns {
+			binds = append(binds, sqlPlaceholder(dbType, i+1))
+		}
+		sql := insert + output + empty + returning
+		if len(columns) > 0 {
+			sql = insert + fmt.Sprintf(" (%s)%s VALUES (%s)", strings.Join(columns, ", "), output, strings.Join(binds, ", ")) + returning
+		}
+		query, args = jen.Lit(sql), values
+	} else {
+		columnLits := make([]jen.Code, 0, len(columns))
+		for _, c := range columns {
+			columnLits = append(columnLits, jen.Lit(c))
+		}
+		body = append(body,
+			jen.Id("columns").Op(":=").Index().String().Values(columnLits...),
+			jen.Id("args").Op(":=").Index().Interface().Values(values...),
+		)
+		for _, f := range defaults {
+			body = append(body, jen.If(jen.Op("!").Qual("reflect", "ValueOf").Call(sqlFieldValue(f)).Dot("IsZero").Call()).Block(
+				jen.Id("columns").Op("=").Append(jen.Id("columns"), jen.Lit(sqlIdent(dbType, f.Field))),
+				jen.Id("args").Op("=").Append(jen.Id("args"), sqlFieldValue(f)),
+			))
+		}
+		bind := jen.Lit(sqlPlaceholder(dbType, 1))
+		if p := strings.TrimSuffix(sqlPlaceholder(dbType, 1), "1"); p != sqlPlaceholder(dbType, 1) {
+			bind = jen.Lit(p).Op("+").Qual("strconv", "Itoa").Call(jen.Id("i").Op("+").Lit(1))
+		}
+		body = append(body,
+			jen.Id("binds").Op(":=").Make(jen.Index().String(), jen.Len(jen.Id("columns"))),
+			jen.For(jen.Id("i").Op(":=").Range().Id("binds")).Block(jen.Id("binds").Index(jen.Id("i")).Op("=").Add(bind)),
+			jen.Id("query").Op(":=").Lit(insert+" (").Op("+").Qual("strings", "Join").Call(jen.Id("columns"), jen.Lit(", ")).
+				Op("+").Lit(")"+output+" VALUES (").Op("+").Qual("strings", "Join").Call(jen.Id("binds"), jen.Lit(", ")).
+				Op("+").Lit(")"+returning),
+		)
+		if len(columns) == 0 {
+			body = append(body, jen.If(jen.Len(jen.Id("columns")).Op("==").Lit(0)).Block(
+				jen.Id("query").Op("=").Lit(insert+output+empty+returning),
+			))
+		}
+		query, args = jen.Id("query"), []jen.Code{jen.Id("args").Op("...")}
+	}
+	call := func(method string) *jen.Statement {
+		return jen.Id("r").Dot("db").Dot(method).Call(append([]jen.Code{jen.Id("ctx"), query}, args...)...)
+	}
+
+	dest := make([]jen.Code, 0, len(returned))
+	for _, f := range returned {
+		dest = append(dest, jen.Op("&").Add(sqlFieldValue(f)))
+	}
+	if returning != "" || output != "" {
+		return append(body, jen.Return(call("QueryRowContext").Dot("Scan").Call(dest...)))
+	}
+
+	// defaults are selected by primary key, which must be known after insert
+	pks := primaryKeys(table.Fields)
+	reselect := len(defaults) > 0 && len(pks) > 0 && dbType != DbTypeRedshift
+	for _, f := range pks {
+		if f != auto && expressionDefault(f) {
+			reselect = false
+		}
+	}
+	if auto == nil || dbType == DbTypeRedshift {
+		// lib/pq of redshift doesn't support LastInsertId
+		if !reselect {
+			return append(body,
+				jen.List(jen.Id("_"), jen.Err()).Op(":=").Add(call("ExecContext")),
+				jen.Return(jen.Err()),
+			)
+		}
+		body = append(body,
+			jen.If(jen.List(jen.Id("_"), jen.Err()).Op(":=").Add(call("ExecContext")), jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err())),
+		)
+	} else {
+		body = append(body,
+			jen.List(jen.Id("res"), jen.Err()).Op(":=").Add(call("ExecContext")),
+			jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err())),
+			jen.List(jen.Id("insertId"), jen.Err()).Op(":=").Id("res").Dot("LastInsertId").Call(),
+			jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err())),
+			sqlFieldValue(auto).Op("=").Id(auto.GoType).Call(jen.Id("insertId")),
+		)
+		if !reselect {
+			return append(body, jen.Return(jen.Nil()))
+		}
+	}
+
+	selected := make([]string, 0, len(defaults))
+	scans := make([]jen.Code, 0, len(defaults))
+	for _, f := range defaults {
+		selected = append(selected, sqlIdent(dbType, f.Field))
+		scans = append(scans, jen.Op("&").Add(sqlFieldValue(f)))
+	}
+	conds := make([]string, 0, len(pks))
+	keys := make([]jen.Code, 0, len(pks))
+	for i, f := range pks {
+		conds = append(conds, sqlIdent(dbType, f.Field)+" = "+sqlPlaceholder(dbType, i+1))
+		keys = append(keys, sqlFieldValue(f))
+	}
+	sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(selected, ", "), sqlIdent(dbType, table.Name), strings.Join(conds, " AND "))
+	return append(body, jen.Return(jen.Id("r").Dot("db").Dot("QueryRowContext").Call(
+		append([]jen.Code{jen.Id("ctx"), jen.Lit(sql)}, keys...)...,
+	).Dot("Scan").Call(scans...)))
+}
+
+// goSqlUpdate body of Update, columns other than primary keys and generated columns are set
+func goSqlUpdate(options *Options, table *Table, pks []*Field) []jen.Code {
+	dbType := options.DbType
+	sets := make([]string, 0, len(table.Fields))
+	args := make([]jen.Code, 0, len(table.Fields)+2)
+	args = append(args, jen.Id("ctx"), jen.Null())
+	for _, f := range table.Fields {
+		if f.Key == "PRI" || f.Generated != "" {
+			continue
+		}
+		sets = append(sets, sqlIdent(dbType, f.Field)+" = "+sqlPlaceholder(dbType, len(sets)+1))
+		args = append(args, sqlFieldValue(f))
+	}
+	if len(sets) == 0 {
+		return []jen.Code{jen.Comment("no columns other than primary keys"), jen.Return(jen.Nil())}
+	}
+
+	conds := make([]string, 0, len(pks))
+	for i, f := range pks {
+		conds = append(conds, sqlIdent(dbType, f.Field)+" = "+sqlPlaceholder(dbType, len(sets)+i+1))
+		args = append(args, sqlFieldValue(f))
+	}
+	args[1] = jen.Lit(fmt.Sprintf("UPDATE %s SET %s WHERE %s", sqlIdent(dbType, table.Name), strings.Join(sets, ", "),
+		strings.Join(conds, " AND ")))
+	return []jen.Code{
+		jen.List(jen.Id("_"), jen.Err()).Op(":=").Id("r").Dot("db").Dot("ExecContext").Call(args...),
+		jen.Return(jen.Err()),
+	}
+}
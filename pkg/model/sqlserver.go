@@ -0,0 +1,274 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	_ "github.com/denisenkom/go-mssqldb"
+	"github.com/jinzhu/gorm"
+)
+
+// sqlserver microsoft sql server, tables of default schema of the login, comments are MS_Description
+// extended properties
+type sqlserver struct{}
+
+func (t *sqlserver) dbStruct(ctx context.Context, options *Options) (tables []*Table, err error) {
+	var db *gorm.DB
+	db, err = connect(options)
+	if err != nil {
+		return
+	}
+
+	if options.Verbose {
+		l.Println("sqlserver dump db struct")
+	}
+
+	tables, err = dumpTables(ctx, options, func(filter *Filter) ([]*Table, error) {
+		return t.filterTables(ctx, db, filter, options)
+	})
+
+	if options.Verbose && err == nil {
+		l.Println("dump completed, table count:", len(tables))
+	}
+
+	return
+}
+
+func (t *sqlserver) filterTables(ctx context.Context, db *gorm.DB, filter *Filter, options *Options) (tables []*Table, err error) {
+	type sqlserverTable struct {
+		Schema  string `gorm:"column:table_schema"`
+		Name    string `gorm:"column:table_name"`
+		Comment string `gorm:"column:table_comment"`
+	}
+
+	var dbTables []*sqlserverTable
+
+	tdb := db.Table("INFORMATION_SCHEMA.TABLES t").
+		Select("t.TABLE_SCHEMA as table_schema, t.TABLE_NAME as table_name, cast(coalesce(ep.value, '') as nvarchar(max)) as table_comment").
+		Joins("left join sys.extended_properties ep on ep.class = 1 and ep.name = 'MS_Description' and ep.minor_id = 0 " +
+			"and ep.major_id = object_id(quotename(t.TABLE_SCHEMA) + '.' + quotename(t.TABLE_NAME))").
+		Where("t.TABLE_SCHEMA = schema_name() and t.TABLE_TYPE = 'BASE TABLE'")
+
+	if filter != nil {
+		l.Println("filter table_name like", filter.TableNamePattern)
+		tdb = tdb.Where("t.TABLE_NAME like ?", filter.TableNamePattern)
+	}
+
+	if len(options.Exclude) > 0 {
+		tdb = tdb.Where("t.TABLE_NAME not in(?)", options.Exclude)
+	}
+
+	err = tdb.Order("t.TABLE_NAME").Find(&dbTables).Error
+	if err != nil {
+		return
+	}
+
+	tables = make([]*Table, 0, len(dbTables))
+	for _, it := range dbTables {
+		tb := &Table{
+			Schema:  it.Schema,
+			Name:    it.Name,
+			Comment: it.Comment,
+		}
+		if filter != nil {
+			tb.Prefix = filter.TablePrefix
+		}
+		tables = append(tables, tb)
+	}
+
+	err = introspectTables(ctx, options, tables, func(tb *Table) (err error) {
+		if tb.Fields, err = t.tableFields(db, tb.Schema, tb.Name); err != nil {
+			return
+		}
+		tb.Ddl = t.tableDdl(tb)
+		return
+	})
+	return
+}
+
+func (t *sqlserver) tableFields(db *gorm.DB, schema, name string) (fields []*Field, err error) {
+	type sqlserverField struct {
+		ColumnName       string `gorm:"column:column_name"`
+		DataType         string `gorm:"column:data_type"`
+		CharLength       int    `gorm:"column:char_length"`
+		NumericPrecision int    `gorm:"column:numeric_precision"`
+		NumericScale     int    `gorm:"column:numeric_scale"`
+		IsNullable       string `gorm:"column:is_nullable"`
+		ColumnDefault    string `gorm:"column:column_default"`
+		Identity         bool   `gorm:"column:is_identity"`
+		PrimaryKey       bool   `gorm:"column:primary_key"`
+		Unique           bool   `gorm:"column:is_unique"`
+		ColumnComment    string `gorm:"column:column_comment"`
+	}
+
+	var dbFields []*sqlserverField
+
+	err = db.Raw(`select c.COLUMN_NAME as column_name, c.DATA_TYPE as data_type,
+       coalesce(c.CHARACTER_MAXIMUM_LENGTH, 0) as char_length,
+       coalesce(c.NUMERIC_PRECISION, 0) as numeric_precision, coalesce(c.NUMERIC_SCALE, 0) as numeric_scale,
+       c.IS_NULLABLE as is_nullable, coalesce(c.COLUMN_DEFAULT, '') as column_default,
+       cast(coalesce(columnproperty(object_id(quotename(c.TABLE_SCHEMA) + '.' + quotename(c.TABLE_NAME)), c.COLUMN_NAME, 'IsIdentity'), 0) as bit) as is_identity,
+       cast(case when exists(select 1 from INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+                                      join INFORMATION_SCHEMA.KEY_COLUMN_USAGE k on k.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA and k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+                             where tc.CONSTRAINT_TYPE = 'PRIMARY KEY' and tc.TABLE_SCHEMA = c.TABLE_SCHEMA and tc.TABLE_NAME = c.TABLE_NAME
+                               and k.COLUMN_NAME = c.COLUMN_NAME) then 1 else 0 end as bit) as primary_key,
+       cast(case when exists(select 1 from INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+                                      join INFORMATION_SCHEMA.KEY_COLUMN_USAGE k on k.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA and k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+                             where tc.CONSTRAINT_TYPE = 'UNIQUE' and tc.TABLE_SCHEMA = c.TABLE_SCHEMA and tc.TABLE_NAME = c.TABLE_NAME
+                               and k.COLUMN_NAME = c.COLUMN_NAME
+                               and (select count(*) from INFORMATION_SCHEMA.KEY_COLUMN_USAGE u
+                                    where u.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA and u.CONSTRAINT_NAME = tc.CONSTRAINT_NAME) = 1) then 1 else 0 end as bit) as is_unique,
+       cast(coalesce(ep.value, '') as nvarchar(max)) as column_comment
+from INFORMATION_SCHEMA.COLUMNS c
+         left join sys.extended_properties ep on ep.class = 1 and ep.name = 'MS_Description'
+    and ep.major_id = object_id(quotename(c.TABLE_SCHEMA) + '.' + quotename(c.TABLE_NAME))
+    and ep.minor_id = columnproperty(ep.major_id, c.COLUMN_NAME, 'ColumnId')
+where c.TABLE_SCHEMA = ? and c.TABLE_NAME = ?
+order by c.ORDINAL_POSITION`, schema, name).Scan(&dbFields).Error
+	if err != nil {
+		return
+	}
+
+	fields = make([]*Field, 0, len(dbFields))
+	for _, it := range dbFields {
+		field := &Field{
+			Field:    it.ColumnName,
+			Type:     t.columnType(it.DataType, it.CharLength, it.NumericPrecision, it.NumericScale),
+			Null:     it.IsNullable,
+			Default:  sqlserverDefault(it.ColumnDefault),
+			Comment:  it.ColumnComment,
+			Nullable: it.IsNullable == "YES",
+		}
+		switch {
+		case it.PrimaryKey:
+			field.Key = "PRI"
+		case it.Unique:
+			field.Key = "UNI"
+		}
+		if it.Identity {
+			field.AutoIncrement = true
+			field.Extra = "identity"
+		}
+
+		field.GoType, _ = t.getGoType(field.Type)
+
+		fields = append(fields, field)
+	}
+
+	return
+}
+
+// columnType data type with length, precision and scale, e.g. nvarchar(64), nvarchar(max), decimal(12,2)
+func (t *sqlserver) columnType(dataType string, length, precision, scale int) string {
+	dataType = strings.ToLower(dataType)
+	switch dataType {
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary":
+		if length < 0 {
+			return dataType + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", dataType, length)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", dataType, precision, scale)
+	}
+	return dataType
+}
+
+// sqlserverDefault default without parentheses wrapped by sql server, e.g. ((0)) -> 0, (getdate()) -> getdate()
+func sqlserverDefault(v string) string {
+	for strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")") {
+		depth := 0
+		for i, ch := range v {
+			switch ch {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			// first parenthesis closes before the end, e.g. (a)+(b)
+			if depth == 0 && i < len(v)-1 {
+				return v
+			}
+		}
+		v = v[1 : len(v)-1]
+	}
+	return v
+}
+
+// tableDdl reconstruct create table statement from fields
+func (t *sqlserver) tableDdl(table *Table) string {
+	lines := make([]string, 0, len(table.Fields)+1)
+	pks := make([]string, 0, 1)
+	for _, f := range table.Fields {
+		line := fmt.Sprintf("  [%s] %s", f.Field, f.Type)
+		if f.AutoIncrement {
+			line += " identity"
+		}
+		if !f.Nullable {
+			line += " not null"
+		}
+		if f.Default != "" {
+			line += " default " + f.Default
+		}
+		lines = append(lines, line)
+		if f.Key == "PRI" {
+			pks = append(pks, fmt.Sprintf("[%s]", f.Field))
+		}
+	}
+	if len(pks) > 0 {
+		lines = append(lines, fmt.Sprintf("  primary key (%s)", strings.Join(pks, ", ")))
+	}
+	return fmt.Sprintf("create table [%s].[%s] (\n%s\n);", table.Schema, table.Name, strings.Join(lines, ",\n"))
+}
+
+func (t *sqlserver) getGoType(dbType string) (string, bool) {
+	// 精确匹配
+	if v, ok := typeSqlserverDic[dbType]; ok {
+		return v, true
+	}
+
+	// 正则匹配
+	for _, v := range typeSqlserverMatch {
+		if ok, _ := regexp.MatchString(v[0], dbType); ok {
+			return v[1], true
+		}
+	}
+
+	return "", false
+}
+
+// typeSqlserverDic matching type
+var typeSqlserverDic = map[string]string{
+	"bit":              "bool",
+	"tinyint":          "uint8",
+	"smallint":         "int16",
+	"int":              "int32",
+	"bigint":           "int64",
+	"real":             "float32",
+	"float":            "float64",
+	"money":            "float64",
+	"smallmoney":       "float64",
+	"text":             "string",
+	"ntext":            "string",
+	"xml":              "string",
+	"uniqueidentifier": "string",
+	"sysname":          "string",
+	"sql_variant":      "string",
+	"image":            "[]byte",
+	"timestamp":        "[]byte",
+	"rowversion":       "[]byte",
+	"date":             "time.Time",
+	"time":             "time.Time",
+	"datetime":         "time.Time",
+	"datetime2":        "time.Time",
+	"smalldatetime":    "time.Time",
+	"datetimeoffset":   "time.Time",
+}
+
+// typeSqlserverMatch regx match types
+var typeSqlserverMatch = [][]string{
+	{`^n?(var)?char[(](\d+|max)[)]$`, "string"},
+	{`^(var)?binary[(](\d+|max)[)]$`, "[]byte"},
+	{`^(decimal|numeric)[(]\d+,\d+[)]$`, "float64"},
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrPrecisionLoss = errors.New("type mapping may lose precision")
+
+// checkStrictTypes returns ErrPrecisionLoss listing all columns whose go type may lose precision,
+// columns in options.StrictTypesAllow (as `table.column`) are skipped
+func checkStrictTypes(options *Options, tables []*Table) error {
+	allow := make(map[string]bool, len(options.StrictTypesAllow))
+	for _, it := range options.StrictTypesAllow {
+		allow[it] = true
+	}
+
+	columns := make([]string, 0)
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			name := fmt.Sprint(table.Name, ".", f.Field)
+			if allow[name] || !lossyType(f.Type, f.GoType) {
+				continue
+			}
+			if _, ok := typeOverride(options, f); ok {
+				continue
+			}
+			columns = append(columns, fmt.Sprintf("%s(%s -> %s)", name, f.Type, f.GoType))
+		}
+	}
+
+	if len(columns) > 0 {
+		return fmt.Errorf("%w: %s", ErrPrecisionLoss, strings.Join(columns, ", "))
+	}
+	return nil
+}
+
+// lossyType returns true if values of db type can not be represented exactly by go type
+func lossyType(dbType, goType string) bool {
+	dbType = strings.ToLower(dbType)
+	switch {
+	case strings.HasPrefix(dbType, "decimal"), strings.HasPrefix(dbType, "numeric"):
+		return goType == "float32" || goType == "float64"
+	case strings.HasPrefix(dbType, "double"):
+		return goType == "float32"
+	case strings.Contains(dbType, "unsigned"):
+		return strings.HasPrefix(goType, "int")
+	}
+	return false
+}
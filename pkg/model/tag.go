@@ -0,0 +1,308 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// names of built-in tag generators
+const (
+	TagGorm     = "gorm"
+	TagJson     = "json"
+	TagEnv      = "env"
+	TagConform  = "conform"
+	TagBoil     = "boil"
+	TagPII      = "pii"
+	TagDb       = "db"
+	TagBun      = "bun"
+	TagXorm     = "xorm"
+	TagValidate = "validate"
+)
+
+var ErrTagGeneratorNotFound = errors.New("tag generator not found")
+
+// TagGenerator struct tags of a column keyed by tag key, keys generated before are kept, e.g. gorm and json.
+// Register with RegisterTagGenerator and enable it by Options.Tags, or add it to Options.TagGenerators
+type TagGenerator interface {
+	Tags(options *Options, table *Table, field *Field) map[string]string
+}
+
+// TagFunc adapter of func as TagGenerator
+type TagFunc func(options *Options, table *Table, field *Field) map[string]string
+
+func (f TagFunc) Tags(options *Options, table *Table, field *Field) map[string]string {
+	return f(options, table, field)
+}
+
+// defaultTags built-in tag generators enabled by their options, e.g. GenGormTag, applied before Options.Tags
+var defaultTags = []string{TagGorm, TagJson, TagEnv, TagConform, TagBoil, TagPII}
+
+var tagGenerators = map[string]TagGenerator{
+	TagGorm:     TagFunc(gormTags),
+	TagJson:     TagFunc(jsonTags),
+	TagEnv:      TagFunc(envTags),
+	TagConform:  TagFunc(conformTags),
+	TagBoil:     TagFunc(boilTags),
+	TagPII:      TagFunc(piiTags),
+	TagDb:       TagFunc(dbTags),
+	TagBun:      TagFunc(bunTags),
+	TagXorm:     TagFunc(xormTags),
+	TagValidate: TagFunc(validateTags),
+}
+
+// RegisterTagGenerator register tag generator by name, usually called in init() of the package providing it,
+// it panics if name is registered twice
+func RegisterTagGenerator(name string, g TagGenerator) {
+	if g == nil {
+		panic("register nil tag generator " + name)
+	}
+	if _, ok := tagGenerators[name]; ok {
+		panic(fmt.Sprintf("tag generator %s registered twice", name))
+	}
+	tagGenerators[name] = g
+}
+
+// checkTagGenerators returns ErrTagGeneratorNotFound if a name of options.Tags is not registered
+func checkTagGenerators(options *Options) error {
+	for _, name := range options.Tags {
+		if _, ok := tagGenerators[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrTagGeneratorNotFound, name)
+		}
+	}
+	return nil
+}
+
+// tagNames names of tag generators of options in order, defaultTags not in options.Tags then options.Tags,
+// so a built-in generator listed in options.Tags is moved to its position
+func tagNames(options *Options) []string {
+	listed := make(map[string]bool, len(options.Tags))
+	for _, name := range options.Tags {
+		listed[name] = true
+	}
+	names := make([]string, 0, len(defaultTags)+len(options.Tags))
+	for _, name := range defaultTags {
+		if !listed[name] {
+			names = append(names, name)
+		}
+	}
+	return append(names, options.Tags...)
+}
+
+// generatedTags add tags of tagNames then options.TagGenerators to tag, keys of tag are kept
+func generatedTags(options *Options, table *Table, f *Field, tag map[string]string) {
+	add := func(g TagGenerator) {
+		for k, v := range g.Tags(options, table, f) {
+			if _, ok := tag[k]; !ok && v != "" {
+				tag[k] = v
+			}
+		}
+	}
+	for _, name := range tagNames(options) {
+		if g, ok := tagGenerators[name]; ok {
+			add(g)
+		}
+	}
+	for _, g := range options.TagGenerators {
+		add(g)
+	}
+}
+
+// gormTags gorm tag of options.GenGormTag, e.g. gorm:"column:id;type:bigint;not null;primaryKey;autoIncrement"
+func gormTags(options *Options, table *Table, f *Field) map[string]string {
+	if !options.GenGormTag {
+		return nil
+	}
+	t := fmt.Sprintf(`column:%s;type:%s`, embeddedColumn(f), f.Type)
+	if f.Default != "" && !f.AutoIncrement {
+		if options.DbManagedDefaults && !options.GormV1 && expressionDefault(f) {
+			t += ";default:(-)"
+		} else {
+			t += fmt.Sprint(";default:", escapeGormTagValue(gormDefault(options, f)))
+		}
+	}
+	if !f.Nullable {
+		t += ";not null"
+	}
+	if f.Key == "PRI" {
+		if options.GormV1 {
+			t += ";primary_key"
+		} else {
+			t += ";primaryKey"
+		}
+	}
+	if f.AutoIncrement {
+		if options.GormV1 {
+			t += ";AUTO_INCREMENT"
+		} else {
+			t += ";autoIncrement"
+		}
+	}
+	if columnIn(options.CreateOnlyColumns, table, f) {
+		t += ";<-:create"
+	}
+	// gorm v1 has no field permission
+	if options.GenComputed && f.Generated != "" && !options.GormV1 {
+		t += ";->;<-:false"
+	}
+	if options.GenGormComment && f.Comment != "" {
+		t += fmt.Sprint(";comment:", escapeGormTagValue(OneLine(f.Comment)))
+	}
+	if options.GenGormCheck && f.Check != "" {
+		t += fmt.Sprint(";check:", escapeGormTagValue(f.CheckName+","+f.Check))
+	}
+	// gorm v1 has no field permission
+	if options.SecretNoRead && !options.GormV1 && secretColumn(options, table, f) {
+		t += ";->:false"
+	}
+	return map[string]string{"gorm": t}
+}
+
+// jsonTags json tag of options.GenJsonTag, secret columns are never serialized even without GenJsonTag
+func jsonTags(options *Options, table *Table, f *Field) map[string]string {
+	if secretColumn(options, table, f) {
+		return map[string]string{"json": "-"}
+	}
+	if !options.GenJsonTag {
+		return nil
+	}
+	return map[string]string{"json": jsonTag(options, f)}
+}
+
+// envTags env or config tag of options.GenEnvTag keyed by EnvTagKey, e.g. env:"USER_NAME"
+func envTags(options *Options, table *Table, f *Field) map[string]string {
+	if !options.GenEnvTag {
+		return nil
+	}
+	return map[string]string{envTagKey(options): envName(options, f.Field)}
+}
+
+// conformTags conform tag of options.GenConformTag, rule of ConformRules or trim of string columns
+func conformTags(options *Options, table *Table, f *Field) map[string]string {
+	if !options.GenConformTag {
+		return nil
+	}
+	if rule, ok := columnOption(options.ConformRules, table, f); ok {
+		if rule == "" {
+			return nil
+		}
+		return map[string]string{"conform": rule}
+	}
+	if _, ok := typeOverride(options, f); !ok && f.GoType == "string" && f.goEnum == nil {
+		return map[string]string{"conform": "trim"}
+	}
+	return nil
+}
+
+// boilTags boil, toml and yaml tags of options.GenBoilTags, toml and yaml of secret columns are -
+func boilTags(options *Options, table *Table, f *Field) map[string]string {
+	if !options.GenBoilTags {
+		return nil
+	}
+	tag := map[string]string{"boil": f.Field, "toml": embeddedColumn(f), "yaml": embeddedColumn(f)}
+	if f.Nullable {
+		tag["yaml"] += ",omitempty"
+	}
+	if secretColumn(options, table, f) {
+		tag["toml"], tag["yaml"] = "-", "-"
+	}
+	return tag
+}
+
+// piiTags pii tag of options.PIIColumns keyed by PIITag, e.g. pii:"true"
+func piiTags(options *Options, table *Table, f *Field) map[string]string {
+	if !columnIn(options.PIIColumns, table, f) {
+		return nil
+	}
+	return map[string]string{piiTag(options): "true"}
+}
+
+// dbTags sqlx and database/sql scanner tag, e.g. db:"user_id"
+func dbTags(options *Options, table *Table, f *Field) map[string]string {
+	return map[string]string{"db": f.Field}
+}
+
+// bunTags bun tag, e.g. bun:"id,pk,autoincrement", bun:"name,notnull"
+func bunTags(options *Options, table *Table, f *Field) map[string]string {
+	t := f.Field
+	if f.Key == "PRI" {
+		t += ",pk"
+	}
+	if f.AutoIncrement {
+		t += ",autoincrement"
+	}
+	if !f.Nullable && f.Key != "PRI" {
+		t += ",notnull"
+	}
+	if f.Key == "UNI" {
+		t += ",unique"
+	}
+	return map[string]string{"bun": t + ",type:" + f.Type}
+}
+
+// xormTags xorm tag, e.g. xorm:"'id' bigint pk autoincr", xorm:"'name' varchar(64) notnull"
+func xormTags(options *Options, table *Table, f *Field) map[string]string {
+	t := fmt.Sprintf("'%s' %s", f.Field, f.Type)
+	if f.Key == "PRI" {
+		t += " pk"
+	}
+	if f.AutoIncrement {
+		t += " autoincr"
+	}
+	if f.Nullable {
+		t += " null"
+	} else {
+		t += " notnull"
+	}
+	if f.Key == "UNI" {
+		t += " unique"
+	}
+	return map[string]string{"xorm": t}
+}
+
+var lengthTypeRegexp = regexp.MustCompile(`^n?(var)?char[(](\d+)[)]`)
+
+// validateTags go-playground validator tag of column constraints: required of not null columns without
+// default whose zero value is invalid, max of char length, min=0 of unsigned non-uint types, oneof of enums;
+// nullable columns are omitempty
+func validateTags(options *Options, table *Table, f *Field) map[string]string {
+	rules := make([]string, 0, 3)
+	if _, ok := typeOverride(options, f); !ok {
+		zeroValid := f.GoType == "bool" || strings.Contains(f.GoType, "int") || strings.HasPrefix(f.GoType, "float")
+		if !f.Nullable && f.Default == "" && !f.AutoIncrement && f.Generated == "" && !zeroValid {
+			rules = append(rules, "required")
+		}
+		if m := lengthTypeRegexp.FindStringSubmatch(strings.ToLower(f.Type)); m != nil && f.GoType == "string" {
+			if n, err := strconv.Atoi(m[2]); err == nil && n > 0 {
+				rules = append(rules, fmt.Sprint("max=", n))
+			}
+		}
+		if strings.Contains(strings.ToLower(f.Type), "unsigned") && !strings.HasPrefix(f.GoType, "uint") {
+			rules = append(rules, "min=0")
+		}
+	}
+	if len(f.EnumValues) > 0 && !strings.HasPrefix(strings.ToLower(f.Type), "set(") {
+		if values := oneOf(f.EnumValues); values != "" {
+			rules = append(rules, "oneof="+values)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	if f.Nullable {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return map[string]string{"validate": strings.Join(rules, ",")}
+}
+
+// oneOf space separated values of validator oneof, empty if a value has space or comma
+func oneOf(values []string) string {
+	for _, v := range values {
+		if v == "" || strings.ContainsAny(v, " ,'") {
+			return ""
+		}
+	}
+	return strings.Join(values, " ")
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"gopkg.in/flosch/pongo2.v3"
+)
+
+// tableTemplate template of Options.CodeTemplateDir rendered for each table, other `*.go.tpl` templates
+// are rendered once for all tables, or once for tables of each shard of Options.ShardOutputByLetter
+const tableTemplate = "table.go.tpl"
+
+// templateFiles render pongo2 templates of options.CodeTemplateDir of prepared tables to gofmt-ed go sources,
+// keyed by file name, templates are not html escaped and may include templates of the dir
+func templateFiles(ctx context.Context, options *Options, tables []*Table) (map[string][]byte, error) {
+	if options.SkipPivotTables {
+		models := make([]*Table, 0, len(tables))
+		for _, table := range tables {
+			if !isPivot(table) {
+				models = append(models, table)
+			}
+		}
+		tables = models
+	}
+
+	if err := checkShardOptions(options); err != nil {
+		return nil, err
+	}
+
+	names, err := filepath.Glob(filepath.Join(options.CodeTemplateDir, "*.go.tpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no *.go.tpl template in %s", options.CodeTemplateDir)
+	}
+
+	set := pongo2.NewSet("code")
+	if err = set.SetBaseDirectory(options.CodeTemplateDir); err != nil {
+		return nil, err
+	}
+
+	pkgName := options.ModelPackageName
+	if pkgName == "" {
+		pkgName = "model"
+	}
+	data := pongo2.Context{
+		"package": pkgName,
+		"header":  fmt.Sprintf("code generated by database-struct @%v", time.Now().Format("2006-01-02 15:04:05")),
+		"license": strings.TrimRight(options.LicenseHeader, "\n"),
+		"options": options,
+		"tables":  tables,
+		"structName": func(table *Table) string {
+			return goStructName(table)
+		},
+		"fieldName": func(f *Field) string {
+			return goFieldName(f)
+		},
+		"fieldType": func(f *Field) string {
+			return goFieldType(options, f, jen.Null()).GoString()
+		},
+		"imports": func(table *Table) []string {
+			return templateImports(options, pkgName, table)
+		},
+	}
+
+	if !options.ShardOutputByLetter {
+		return renderTemplates(ctx, set, names, data, tables, "")
+	}
+
+	// each shard is a package rendered by all templates with tables of the shard
+	shards, dirs := letterShards(tables)
+	files := make(map[string][]byte, len(tables)+len(names)*len(dirs))
+	for _, dir := range dirs {
+		shardData := pongo2.Context{}
+		shardData.Update(data)
+		shardData["tables"] = shards[dir]
+		shardFiles, err := renderTemplates(ctx, set, names, shardData, shards[dir], dir)
+		if err != nil {
+			return nil, err
+		}
+		for file, b := range shardFiles {
+			files[file] = b
+		}
+	}
+	return files, nil
+}
+
+// renderTemplates render templates of names to files in dir, table.go.tpl for each table
+func renderTemplates(ctx context.Context, set *pongo2.TemplateSet, names []string, data pongo2.Context, tables []*Table, dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(tables)+len(names))
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		tpl, err := set.FromString("{% autoescape off %}" + string(b) + "{% endautoescape %}")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		base := filepath.Base(name)
+		if base != tableTemplate {
+			file := filepath.ToSlash(filepath.Join(dir, strings.TrimSuffix(base, ".tpl")))
+			if files[file], err = renderTemplate(tpl, data, file); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, table := range tables {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+			file := filepath.ToSlash(filepath.Join(dir, strings.TrimPrefix(table.Name, table.Prefix)+".go"))
+			tableData := pongo2.Context{"table": table, "fields": table.Fields}
+			tableData.Update(data)
+			if files[file], err = renderTemplate(tpl, tableData, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+func renderTemplate(tpl *pongo2.Template, data pongo2.Context, file string) ([]byte, error) {
+	code, err := tpl.Execute(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	b, err := format.Source([]byte(code))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return b, nil
+}
+
+// templateImports import paths of the go struct of table, sorted
+func templateImports(options *Options, pkgName string, table *Table) []string {
+	if table.goStatement == nil {
+		return nil
+	}
+	f := newFile(options, pkgName, "")
+	f.Add(table.goStatement)
+	parsed, err := parser.ParseFile(token.NewFileSet(), "", f.GoString(), parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	paths := make([]string, 0, len(parsed.Imports))
+	for _, it := range parsed.Imports {
+		if path, err := strconv.Unquote(it.Path.Value); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,9 @@
+drop table if exists collation_user;
+
+create table collation_user
+(
+    id       bigint auto_increment primary key,
+    name     varchar(64) collate utf8mb4_general_ci not null,
+    token    varchar(64) collate utf8mb4_bin        not null,
+    password varchar(64) collate utf8mb4_bin
+) default charset = utf8mb4;
@@ -0,0 +1,32 @@
+drop table if exists seq_serial;
+drop table if exists seq_identity;
+drop table if exists fk_post;
+drop table if exists fk_author;
+drop sequence if exists seq_invoice_no;
+
+create table seq_serial
+(
+    id   serial primary key,
+    name varchar(64) not null default ''
+);
+
+create table seq_identity
+(
+    id   bigint generated always as identity primary key,
+    name text
+);
+
+create sequence seq_invoice_no start 1000;
+
+create table fk_author
+(
+    id   bigint primary key,
+    name text not null
+);
+
+create table fk_post
+(
+    id        bigint primary key,
+    author_id bigint not null references fk_author (id) on delete cascade,
+    editor_id bigint references fk_author (id) on delete set null on update cascade
+);
@@ -0,0 +1,57 @@
+package model
+
+import "strings"
+
+// go types of unsigned bigint primary keys, see Options.UnsignedBigintPK
+const (
+	UnsignedPKString     = "string"
+	UnsignedPKJSONString = "json-string"
+)
+
+// unsignedBigint returns true if field is `bigint unsigned`
+func unsignedBigint(f *Field) bool {
+	return strings.HasPrefix(f.Type, "bigint") && strings.Contains(f.Type, "unsigned")
+}
+
+// resolveUnsignedPKs map go type of unsigned bigint primary keys, and of unsigned bigint foreign keys referencing
+// them, to string if options.UnsignedBigintPK is string, or back to uint64
+func resolveUnsignedPKs(options *Options, tables []*Table) {
+	pks := make(map[string]bool)
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			f.unsignedKey = f.Key == "PRI" && unsignedBigint(f)
+			if f.unsignedKey {
+				pks[table.Name+"."+f.Field] = true
+			}
+		}
+	}
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			if f := ddlField(table, fk.Column); f != nil && unsignedBigint(f) && pks[fk.RefTable+"."+fk.RefColumn] {
+				f.unsignedKey = true
+			}
+		}
+	}
+
+	for _, table := range tables {
+		for _, f := range table.Fields {
+			if !f.unsignedKey {
+				continue
+			}
+			switch {
+			case options.UnsignedBigintPK == UnsignedPKString:
+				f.GoType = "string"
+			case f.GoType == "string":
+				f.GoType = "uint64"
+			}
+		}
+	}
+}
+
+// jsonString returns true if json tag of field has `,string`
+func jsonString(options *Options, f *Field) bool {
+	if !options.JsonInt64AsString && !(options.UnsignedBigintPK == UnsignedPKJSONString && f.unsignedKey) {
+		return false
+	}
+	return jsonStringInt(options, f)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -9,6 +10,7 @@ var (
 	numberSequence    = regexp.MustCompile(`([a-zA-Z])(\d+)([a-zA-Z]?)`)
 	numberReplacement = []byte(`$1 $2 $3`)
 	linebreak         = regexp.MustCompile(`[\n\r]+`)
+	versionSuffix     = regexp.MustCompile(`[.]v\d+$`)
 )
 
 func TitleCase(str string) string {
@@ -19,10 +21,108 @@ func CamelCase(str string) string {
 	return toCamelCase(str, false)
 }
 
+// SnakeCase lower case words separated by underscore, e.g. UserID -> user_id
+func SnakeCase(str string) string {
+	var b strings.Builder
+	rs := []rune(strings.TrimSpace(str))
+	for i, r := range rs {
+		switch {
+		case r == ' ' || r == '-':
+			r = '_'
+		case r >= 'A' && r <= 'Z':
+			// word starts at upper case after lower case or digit, or before lower case in acronym, e.g. ID|Card
+			if i > 0 && rs[i-1] != '_' && (isLowerOrDigit(rs[i-1]) || i+1 < len(rs) && rs[i+1] >= 'a' && rs[i+1] <= 'z' && rs[i-1] >= 'A' && rs[i-1] <= 'Z') {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isLowerOrDigit(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9'
+}
+
 func OneLine(str string) string {
 	return linebreak.ReplaceAllString(str, "")
 }
 
+// escapeTagValue escape struct tag value, so quotes and backslashes keep the conventional tag syntax
+func escapeTagValue(v string) string {
+	q := strconv.Quote(v)
+	return q[1 : len(q)-1]
+}
+
+// escapeGormTagValue escape semicolons which separate gorm tag settings
+func escapeGormTagValue(v string) string {
+	return strings.ReplaceAll(v, ";", `\;`)
+}
+
+// gormDefault default of gorm tag, gorm v2 puts default of non string fields into ddl as is,
+// so their literal with spaces is quoted, defaults of string fields are quoted by gorm itself,
+// quoted literal and expression are kept
+func gormDefault(options *Options, f *Field) string {
+	v := f.Default
+	if options.GormV1 || !strings.ContainsAny(v, " \t") || strings.HasPrefix(v, "'") || strings.Contains(v, "(") {
+		return v
+	}
+	goType := f.GoType
+	if t, ok := typeOverride(options, f); ok {
+		goType = t
+	}
+	if goType == "string" || f.goEnum != nil && f.goEnum.Ints == nil {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// sqlDefaultKeywords defaults computed by db without parentheses
+var sqlDefaultKeywords = map[string]bool{
+	"CURRENT_TIMESTAMP": true,
+	"CURRENT_DATE":      true,
+	"CURRENT_TIME":      true,
+	"LOCALTIME":         true,
+	"LOCALTIMESTAMP":    true,
+}
+
+// expressionDefault returns true if default of field is computed by db, e.g. CURRENT_TIMESTAMP, now(), uuid()
+func expressionDefault(f *Field) bool {
+	if f.Default == "" || strings.HasPrefix(f.Default, "'") {
+		return false
+	}
+	return strings.Contains(f.Extra, "DEFAULT_GENERATED") || strings.Contains(f.Default, "(") ||
+		sqlDefaultKeywords[strings.ToUpper(f.Default)]
+}
+
+// stdPackages import path of standard packages referred by name
+var stdPackages = map[string]string{
+	"sql":  "database/sql",
+	"json": "encoding/json",
+}
+
+// qualifiedType split qualified type into import path and type name,
+// e.g. github.com/shopspring/decimal.Decimal -> github.com/shopspring/decimal, Decimal
+func qualifiedType(s string) (path, name string) {
+	i := strings.LastIndex(s, ".")
+	if i < 0 || i < strings.LastIndex(s, "/") {
+		return "", s
+	}
+
+	path, name = s[:i], s[i+1:]
+	if p, ok := stdPackages[path]; ok {
+		path = p
+	}
+	return path, name
+}
+
+// packageName guess package name of import path, e.g. gopkg.in/guregu/null.v4 -> null
+func packageName(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	return versionSuffix.ReplaceAllString(name, "")
+}
+
 func addWordBoundariesToNumbers(s string) string {
 	b := []byte(s)
 	b = numberSequence.ReplaceAll(b, numberReplacement)